@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+// handSize is the number of cards eval expects.
+const handSize = 5
+
+// eval tells what a hand is and what it scores under the house rules.
+func eval(args []string, in io.Reader, out io.Writer) error {
+	fs := newFlags("eval")
+	rulesPath := fs.String("rules", "", "path to a JSON house-rules file whose scoring is used")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*rulesPath)
+	if err != nil {
+		return fmt.Errorf("invalid rules file: %w", err)
+	}
+	if fs.NArg() != handSize {
+		fmt.Fprintf(fs.Output(), "eval takes a hand of %d cards, such as AH KH QH JH 10H, got %d\n", handSize, fs.NArg())
+		return errUsage
+	}
+
+	hand := make([]cards.Card, 0, handSize)
+	for _, arg := range fs.Args() {
+		card, err := cards.Parse(arg)
+		if err != nil {
+			return err
+		}
+		for _, held := range hand {
+			if held == card {
+				return fmt.Errorf("%s is in the hand twice", arg)
+			}
+		}
+		hand = append(hand, card)
+	}
+
+	eval := game.EvaluateHand(hand)
+	fmt.Fprintf(out, "%v, worth %d points (%s scoring)\n", eval.Rank, cfg.Rules.Scoring.HandPoints(eval.Rank), cfg.Rules.Scoring.Name)
+	fmt.Fprintf(out, "Scoring cards: %s\n", cardList(eval.ScoreCards))
+	if len(eval.Kickers) > 0 {
+		fmt.Fprintf(out, "Kickers: %s\n", cardList(eval.Kickers))
+	}
+	return nil
+}
+
+// cardList writes cards the way eval reads them.
+func cardList(list []cards.Card) string {
+	names := make([]string, len(list))
+	for i, card := range list {
+		rank := cards.RankToString(card.Rank)
+		if card.Rank > cards.Ten {
+			rank = rank[:1]
+		}
+		names[i] = rank + string(card.Suit)
+	}
+	return strings.Join(names, " ")
+}
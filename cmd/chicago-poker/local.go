@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/internal/gameLocal"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+)
+
+// local plays a game on this terminal, with the players passing it around.
+// Hands are hidden from the others unless -open is given.
+func local(args []string, in io.Reader, out io.Writer) error {
+	fs := newFlags("local")
+	rulesPath := fs.String("rules", "", "path to a JSON house-rules file (see rules.example.json)")
+	seed := fs.Int64("seed", 0, "seed of the deck, 0 for a random game")
+	open := fs.Bool("open", false, "show every hand without clearing the screen between players")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*rulesPath)
+	if err != nil {
+		return fmt.Errorf("invalid rules file: %w", err)
+	}
+
+	names := fs.Args()
+	if len(names) == 0 {
+		names = []string{"Player1", "Player2"}
+	}
+	players := make([]*player.Player, len(names))
+	for i, name := range names {
+		players[i] = player.NewPlayer(name)
+	}
+	g := game.NewGame(players)
+	if *seed != 0 {
+		g = game.NewSeededGame(players, *seed)
+	}
+	g.Rules = cfg.Rules
+	if *open {
+		return gameLocal.Run(g, in, out)
+	}
+	return gameLocal.RunHotSeat(g, in, out)
+}
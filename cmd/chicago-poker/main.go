@@ -1,35 +1,124 @@
 package main
 
 import (
-	"github.com/antongollbo123/chicago-poker/internal/gameNetwork"
-	"github.com/antongollbo123/chicago-poker/internal/player"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/antongollbo123/chicago-poker/internal/config"
 )
 
-func main() {
-	// Initialize the GameServer
-	gameServer := &gameNetwork.GameServer{
-		Clients: make(map[*gameNetwork.Client]bool),
+// command is one mode of the binary. It is run with the arguments that follow
+// its name and reads from in and writes to out.
+type command struct {
+	name    string
+	usage   string // arguments after the flags
+	summary string
+	run     func(args []string, in io.Reader, out io.Writer) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "", "run the game server for netcat and browser players", serve},
+		{"local", "[name...]", "play a hot-seat game on this terminal", local},
+		{"simulate", "", "play games between bots and report the results", simulate},
+		{"replay", "file", "show a saved game record event by event", replay},
+		{"eval", "card...", "evaluate a poker hand such as AH KH QH JH 10H", eval},
 	}
+}
 
-	// Start the GameServer
-	go gameServer.BuildServer()
+// errUsage reports arguments that were wrong; the flag set has already said
+// what was wrong with them.
+var errUsage = errors.New("usage")
 
-	// Wait for players to join
-	players := make([]*player.Player, 0) // Slice to hold connected players
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout))
+}
 
-	// Listen for players and start the game when enough are connected
-	go func() {
-		for {
-			if len(players) >= 4 { // Change this number as needed
-				break // Start the game when enough players are connected
-			}
-			// This is a simple loop to simulate waiting for players
-			// In a real application, you would handle this in the handleConnection method
+// run picks the command named by the first argument, serve if there is none,
+// runs it and returns the exit code.
+func run(args []string, in io.Reader, out io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if len(args) == 1 && (args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+			usage(out)
+			return 0
+		}
+		// Flags alone start the server, as they always have
+		args = append([]string{"serve"}, args...)
+	}
+	name, args := args[0], args[1:]
+	if name == "help" {
+		usage(out)
+		return 0
+	}
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(args, in, out)
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
 		}
+		fmt.Fprintf(os.Stderr, "chicago-poker %s: %v\n", name, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "chicago-poker: unknown command %q\n\n", name)
+	usage(os.Stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: chicago-poker <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command the server is started. Run 'chicago-poker <command> -help'")
+	fmt.Fprintln(w, "for the flags of a command.")
+}
+
+// newFlags returns the flag set of a command, whose help describes it.
+func newFlags(name string) *flag.FlagSet {
+	var cmd command
+	for _, c := range commands {
+		if c.name == name {
+			cmd = c
+		}
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		w := fs.Output()
+		fmt.Fprintf(w, "Usage: %s\n\n", strings.TrimSpace("chicago-poker "+name+" [flags] "+cmd.usage))
+		fmt.Fprintf(w, "%s%s.\n\nFlags:\n", strings.ToUpper(cmd.summary[:1]), cmd.summary[1:])
+		fs.PrintDefaults()
+	}
+	return fs
+}
 
-		gameServer.Game.StartGame(gameServer) // Start the game
-	}()
+// parseFlags parses a command's arguments, turning errors other than a
+// request for help into errUsage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
+	}
+	return err
+}
 
-	// Blocking main goroutine
-	select {} // Keeps the main function running
+// loadConfig reads a rules file, or returns the defaults if path is empty.
+func loadConfig(path string) (config.Config, error) {
+	if path == "" {
+		return config.Default(), nil
+	}
+	return config.Load(path)
 }
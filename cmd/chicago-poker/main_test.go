@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+)
+
+// runCommand runs the binary with the given arguments and returns its exit
+// code and output.
+func runCommand(t *testing.T, stdin string, args ...string) (int, string) {
+	t.Helper()
+	var out bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &out)
+	return code, out.String()
+}
+
+func TestEval(t *testing.T) {
+	code, out := runCommand(t, "", "eval", "9s", "9H", "2d", "2♣", "KD")
+	if code != 0 || !strings.HasPrefix(out, "Two Pair, worth 2 points") || !strings.Contains(out, "Kickers: K♦") {
+		t.Errorf("eval = %d, %q", code, out)
+	}
+	if code, _ := runCommand(t, "", "eval", "AH", "KH"); code != 2 {
+		t.Errorf("eval of two cards exited with %d, want 2", code)
+	}
+	if code, _ := runCommand(t, "", "eval", "AH", "AH", "2C", "3C", "4C"); code != 1 {
+		t.Errorf("eval of a hand holding a card twice exited with %d, want 1", code)
+	}
+}
+
+func TestSimulateIsReproducible(t *testing.T) {
+	args := []string{"simulate", "-games", "5", "-players", "3", "-seed", "42"}
+	code, first := runCommand(t, "", args...)
+	if code != 0 || !strings.Contains(first, "5 games of 3 bot players") {
+		t.Fatalf("simulate = %d, %q", code, first)
+	}
+	if _, second := runCommand(t, "", args...); second != first {
+		t.Errorf("the same seeds gave\n%s\nand\n%s", first, second)
+	}
+	for _, players := range []string{"-1", "1", "9"} {
+		if code, _ := runCommand(t, "", "simulate", "-games", "1", "-players", players); code != 1 {
+			t.Errorf("simulate with %s players exited with %d, want 1", players, code)
+		}
+	}
+}
+
+func TestReplayFromStandardInput(t *testing.T) {
+	g, _, err := playBotGame(game.DefaultRules(), 2, 7, game.FallbackAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := json.Marshal(g.Record())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, out := runCommand(t, "2024/01/02 15:04:05 Game record of table friday: "+string(record), "replay", "-")
+	if code != 0 || !strings.Contains(out, "wins the game") {
+		t.Errorf("replay = %d, %q", code, out)
+	}
+	if code, _ := runCommand(t, "{}", "replay"); code != 2 {
+		t.Errorf("replay without a file exited with %d, want 2", code)
+	}
+}
+
+func TestHelpAndUnknownCommands(t *testing.T) {
+	if code, out := runCommand(t, "", "help"); code != 0 || !strings.Contains(out, "simulate") {
+		t.Errorf("help = %d, %q", code, out)
+	}
+	for _, cmd := range commands {
+		if code, _ := runCommand(t, "", cmd.name, "-help"); code != 0 {
+			t.Errorf("%s -help exited with %d, want 0", cmd.name, code)
+		}
+	}
+	if code, _ := runCommand(t, "", "deal"); code != 2 {
+		t.Errorf("an unknown command exited with %d, want 2", code)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+)
+
+// replay shows a game from its record: a game saved at shutdown, or a
+// "Game record" line from the server log.
+func replay(args []string, in io.Reader, out io.Writer) error {
+	fs := newFlags("replay")
+	private := fs.Bool("cards", false, "also show the cards each player was dealt, tossed and drew")
+	step := fs.Bool("step", false, "wait for Enter before each new hand")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(fs.Output(), "replay takes one file, or - to read standard input")
+		return errUsage
+	}
+
+	if *step && fs.Arg(0) == "-" {
+		fmt.Fprintln(fs.Output(), "-step waits for Enter on standard input, so the record must come from a file")
+		return errUsage
+	}
+
+	var data []byte
+	var err error
+	if path := fs.Arg(0); path == "-" {
+		data, err = io.ReadAll(in)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	// A line from the server log carries the record after its prefix
+	if start := bytes.IndexByte(data, '{'); start > 0 {
+		data = data[start:]
+	}
+	var record game.Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return fmt.Errorf("not a game record: %w", err)
+	}
+	// Check the whole record before showing any of it
+	g, err := game.Replay(record)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Players: %s\n", strings.Join(record.Players, ", "))
+	fmt.Fprintf(out, "House rules: %s\n", record.Rules.Summary())
+	if record.Key != nil {
+		fmt.Fprintf(out, "Key: %x\n", *record.Key)
+	} else {
+		fmt.Fprintf(out, "Seed: %d\n", record.Seed)
+	}
+	keys := bufio.NewScanner(in)
+	for i, e := range record.Events {
+		if *step && e.Type == game.EventNewHand && i > 0 {
+			fmt.Fprint(out, "Press Enter for the next hand...")
+			if !keys.Scan() {
+				return keys.Err()
+			}
+		}
+		line := g.Describe(e)
+		if *private && e.Private() {
+			line += fmt.Sprintf(": %v", e.Cards)
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	if g.Stage != game.Over {
+		fmt.Fprintf(out, "The game stopped during the %s stage.\n", g.Stage)
+	}
+	fmt.Fprintln(out, "Scores:")
+	for _, p := range g.Players {
+		fmt.Fprintf(out, "  %s: %d\n", p.Name, p.Score)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/antongollbo123/chicago-poker/internal/gameNetwork"
+)
+
+// serve runs the game server until it is told to stop. Flags override the
+// rules file.
+func serve(args []string, in io.Reader, out io.Writer) error {
+	fs := newFlags("serve")
+	addr := fs.String("addr", gameNetwork.DefaultAddr, "address to accept netcat players on")
+	webAddr := fs.String("web", gameNetwork.DefaultWebAddr, "address to accept WebSocket players on, empty to disable")
+	origins := fs.String("origins", "", "comma-separated origins of other web pages that may open a WebSocket")
+	rulesPath := fs.String("rules", "", "path to a JSON house-rules file (see rules.example.json)")
+	maxTables := fs.Int("max-tables", 0, "tables the lobby holds at once (default from the rules, 16)")
+	maxPlayers := fs.Int("max-players", 0, "seats at each table (default from the rules, 8)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "serve takes no arguments, got %q\n", fs.Args())
+		return errUsage
+	}
+
+	cfg, err := loadConfig(*rulesPath)
+	if err != nil {
+		return fmt.Errorf("invalid rules file: %w", err)
+	}
+	if *maxTables != 0 {
+		cfg.MaxTables = *maxTables
+	}
+	if *maxPlayers != 0 {
+		cfg.Rules.MaxPlayers = *maxPlayers
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "House rules:", cfg.Rules.Summary())
+
+	// Initialize the GameServer and serve until the process is told to stop
+	gameServer := gameNetwork.NewGameServer(cfg.Rules)
+	gameServer.Addr = *addr
+	gameServer.LobbyWait = cfg.LobbyWait
+	gameServer.MaxTables = cfg.MaxTables
+	gameServer.ReconnectGrace = cfg.ReconnectGrace
+	gameServer.TurnTimeout = cfg.TurnTimeout
+	gameServer.TimeoutPolicy = cfg.TimeoutPolicy
+	gameServer.AwayAfter = cfg.AwayAfter
+	gameServer.ShutdownWait = cfg.ShutdownWait
+	gameServer.SaveDir = cfg.SaveDir
+	gameServer.WebAddr = *webAddr
+	if *origins != "" {
+		gameServer.WebOrigins = strings.Split(*origins, ",")
+	}
+
+	// The first signal shuts down gracefully; a second one, with signal
+	// handling then back to the default, stops the process at once
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	go func() {
+		<-stop
+		signal.Stop(stop)
+		gameServer.Shutdown()
+		close(stopped)
+	}()
+
+	if err := gameServer.BuildServer(); err != nil {
+		signal.Stop(stop)
+		return err
+	}
+	<-stopped
+	return nil
+}
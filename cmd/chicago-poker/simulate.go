@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+)
+
+// maxMoves stops a simulated game that does not end, which would be a bug.
+const maxMoves = 100000
+
+// simulate plays games between bots and reports how they went, which is
+// handy for seeing what house rules do to a game.
+func simulate(args []string, in io.Reader, out io.Writer) error {
+	fs := newFlags("simulate")
+	rulesPath := fs.String("rules", "", "path to a JSON house-rules file (see rules.example.json)")
+	games := fs.Int("games", 100, "number of games to play")
+	players := fs.Int("players", 4, "bots at the table")
+	seed := fs.Int64("seed", 1, "seed of the first game; each next game adds one")
+	policy := fs.String("policy", string(game.FallbackBot), "how the bots play: \"bot\" or \"auto\"")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*rulesPath)
+	if err != nil {
+		return fmt.Errorf("invalid rules file: %w", err)
+	}
+	fallback := game.Fallback(*policy)
+	if err := fallback.Validate(); err != nil {
+		return err
+	}
+	if *games < 1 {
+		return fmt.Errorf("need at least one game, got %d", *games)
+	}
+	if *players < cfg.Rules.MinPlayers || *players > cfg.Rules.MaxPlayers {
+		return fmt.Errorf("the rules seat %d to %d players, got %d", cfg.Rules.MinPlayers, cfg.Rules.MaxPlayers, *players)
+	}
+
+	wins := make([]int, *players)
+	scores := make([]int, *players)
+	showdowns := make(map[game.HandRank]int)
+	hands, moves := 0, 0
+	for i := 0; i < *games; i++ {
+		g, played, err := playBotGame(cfg.Rules, *players, *seed+int64(i), fallback)
+		if err != nil {
+			return fmt.Errorf("game with seed %d: %w", *seed+int64(i), err)
+		}
+		moves += played
+		for _, e := range g.Log() {
+			switch e.Type {
+			case game.EventNewHand:
+				hands++
+			case game.EventHandScored:
+				showdowns[e.Rank]++
+			case game.EventGameOver:
+				wins[e.Player]++
+			}
+		}
+		for seat, p := range g.Players {
+			scores[seat] += p.Score
+		}
+	}
+
+	fmt.Fprintf(out, "%d games of %d %s players, seeds %d to %d\n", *games, *players, fallback, *seed, *seed+int64(*games-1))
+	fmt.Fprintf(out, "House rules: %s\n\n", cfg.Rules.Summary())
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Seat\tWins\tAverage score")
+	for seat := range wins {
+		fmt.Fprintf(w, "%d\t%d\t%.1f\n", seat, wins[seat], float64(scores[seat])/float64(*games))
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\nPer game: %.1f hands, %.1f moves\n\n", float64(hands)/float64(*games), float64(moves)/float64(*games))
+	fmt.Fprintln(w, "Showdowns won with\tCount")
+	for rank := game.HighCard; rank <= game.RoyalStraightFlush; rank++ {
+		if showdowns[rank] > 0 {
+			fmt.Fprintf(w, "%v\t%d\n", rank, showdowns[rank])
+		}
+	}
+	return w.Flush()
+}
+
+// playBotGame plays one game to its end with every move made by fallback,
+// and returns it with the number of moves made.
+func playBotGame(rules game.Rules, players int, seed int64, fallback game.Fallback) (*game.Game, int, error) {
+	seats := make([]*player.Player, players)
+	for i := range seats {
+		seats[i] = player.NewPlayer(fmt.Sprintf("Bot%d", i+1))
+	}
+	g := game.NewSeededGame(seats, seed)
+	g.Rules = rules
+	if _, err := g.Start(); err != nil {
+		return nil, 0, err
+	}
+	moves := 0
+	for ; g.Stage != game.Over; moves++ {
+		if moves == maxMoves {
+			return nil, moves, fmt.Errorf("no winner after %d moves", maxMoves)
+		}
+		if _, err := g.Apply(fallback.Action(g, g.Turn())); err != nil {
+			return nil, moves, err
+		}
+	}
+	return g, moves, nil
+}
@@ -0,0 +1,269 @@
+// Package config loads the house rules a server plays with from a JSON file.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+)
+
+// DefaultTurnTimeout is how long a player has to make a move.
+const DefaultTurnTimeout = time.Minute
+
+// DefaultAwayAfter is how many turns in a row a player may run out of time
+// on before they are marked away.
+const DefaultAwayAfter = 3
+
+// DefaultLobbyWait is how long a table waits for its last players to vote
+// ready once most of its players have.
+const DefaultLobbyWait = 5 * time.Second
+
+// DefaultMaxTables is how many tables the lobby holds at once.
+const DefaultMaxTables = 16
+
+// DefaultReconnectGrace is how long a seat is kept for a player whose
+// connection drops during a game.
+const DefaultReconnectGrace = time.Minute
+
+// DefaultSaveDir is where games still running at shutdown are saved.
+const DefaultSaveDir = "saved_games"
+
+// Config is everything a server can be told by its rules file. Fields missing
+// from the file keep their defaults.
+type Config struct {
+	Rules game.Rules
+
+	// TurnTimeout is how long a player has to act before a move is made for
+	// them. Zero waits forever.
+	TurnTimeout time.Duration
+	// TimeoutPolicy is how the move of a player who runs out of time is made.
+	TimeoutPolicy game.Fallback
+	// AwayAfter is how many turns in a row a player may run out of time on
+	// before they are marked away and their moves are made without waiting.
+	// Zero never marks anyone away.
+	AwayAfter int
+	// LobbyWait is how long a table waits for its last players to vote ready
+	// once most of its players have.
+	LobbyWait time.Duration
+	// MaxTables is how many tables the lobby holds at once.
+	MaxTables int
+	// ReconnectGrace is how long a player whose connection drops during a
+	// game has to take their seat back.
+	ReconnectGrace time.Duration
+	// ShutdownWait is how long running hands get to finish when the server
+	// is shut down. Zero stops every game at once.
+	ShutdownWait time.Duration
+	// SaveDir is where games still in progress at shutdown are saved. Empty
+	// does not save them.
+	SaveDir string
+}
+
+// Default returns the configuration used when no rules file is given, which
+// is what the server does out of the box.
+func Default() Config {
+	return Config{
+		Rules:          game.DefaultRules(),
+		TurnTimeout:    DefaultTurnTimeout,
+		TimeoutPolicy:  game.FallbackAuto,
+		AwayAfter:      DefaultAwayAfter,
+		LobbyWait:      DefaultLobbyWait,
+		MaxTables:      DefaultMaxTables,
+		ReconnectGrace: DefaultReconnectGrace,
+		SaveDir:        DefaultSaveDir,
+	}
+}
+
+// file is the on-disk layout of a rules file. The scoring table may be given
+// either as the name of a preset or as a table of its own.
+type file struct {
+	MinPlayers   *int               `json:"min_players"`
+	MaxPlayers   *int               `json:"max_players"`
+	TargetScore  *int               `json:"target_score"`
+	EndCondition *game.EndCondition `json:"end_condition"`
+	Roof         *int               `json:"roof"`
+	Exchanges    *int               `json:"exchanges"`
+	Announce     *bool              `json:"announce"`
+	TiePolicy    *game.TiePolicy    `json:"tie_policy"`
+	Scoring      json.RawMessage    `json:"scoring"`
+	TrickBonuses []game.TrickBonus  `json:"trick_bonuses"`
+	TurnTimeout  *Duration          `json:"turn_timeout"`
+	Timeout      *game.Fallback     `json:"timeout_policy"`
+	AwayAfter    *int               `json:"away_after"`
+	LobbyWait    *Duration          `json:"lobby_wait"`
+	MaxTables    *int               `json:"max_tables"`
+	Reconnect    *Duration          `json:"reconnect_grace"`
+	ShutdownWait *Duration          `json:"shutdown_wait"`
+	SaveDir      *string            `json:"save_dir"`
+}
+
+// Duration is a time.Duration written as a string such as "30s" or "2m".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("durations are strings such as \"30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Load reads and validates a rules file.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse builds a configuration from the contents of a rules file and
+// validates it.
+func Parse(data []byte) (Config, error) {
+	var f file
+	if err := decodeStrict(data, &f); err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+	setInt(&cfg.Rules.MinPlayers, f.MinPlayers)
+	setInt(&cfg.Rules.MaxPlayers, f.MaxPlayers)
+	setInt(&cfg.Rules.TargetScore, f.TargetScore)
+	setInt(&cfg.Rules.Roof, f.Roof)
+	setInt(&cfg.Rules.Exchanges, f.Exchanges)
+	if f.EndCondition != nil {
+		cfg.Rules.EndCondition = *f.EndCondition
+	}
+	if f.Announce != nil {
+		cfg.Rules.Announce = *f.Announce
+	}
+	if f.TiePolicy != nil {
+		cfg.Rules.TiePolicy = *f.TiePolicy
+	}
+	if len(f.Scoring) > 0 {
+		scoring, err := parseScoring(f.Scoring)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.Rules.Scoring = scoring
+	}
+	if f.TrickBonuses != nil {
+		cfg.Rules.TrickBonuses = f.TrickBonuses
+	}
+	if f.TurnTimeout != nil {
+		cfg.TurnTimeout = time.Duration(*f.TurnTimeout)
+	}
+	if f.Timeout != nil {
+		cfg.TimeoutPolicy = *f.Timeout
+	}
+	setInt(&cfg.AwayAfter, f.AwayAfter)
+	if f.LobbyWait != nil {
+		cfg.LobbyWait = time.Duration(*f.LobbyWait)
+	}
+	setInt(&cfg.MaxTables, f.MaxTables)
+	if f.Reconnect != nil {
+		cfg.ReconnectGrace = time.Duration(*f.Reconnect)
+	}
+	if f.ShutdownWait != nil {
+		cfg.ShutdownWait = time.Duration(*f.ShutdownWait)
+	}
+	if f.SaveDir != nil {
+		cfg.SaveDir = *f.SaveDir
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// parseScoring accepts a preset name, or a table that starts from the preset
+// it names (the default table if it names none) and overrides its points.
+func parseScoring(data []byte) (game.ScoringRules, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		scoring, err := game.ScoringPreset(name)
+		if err != nil {
+			return game.ScoringRules{}, fmt.Errorf("scoring: %w", err)
+		}
+		return scoring, nil
+	}
+
+	var named struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &named); err != nil {
+		return game.ScoringRules{}, fmt.Errorf("scoring must be a preset name or a table: %w", err)
+	}
+	scoring, err := game.ScoringPreset(named.Name)
+	if err != nil {
+		scoring = game.DefaultRules().Scoring
+		scoring.Name = named.Name
+		if scoring.Name == "" {
+			scoring.Name = "custom"
+		}
+	}
+	if err := decodeStrict(data, &scoring); err != nil {
+		return game.ScoringRules{}, fmt.Errorf("scoring: %w", err)
+	}
+	return scoring, nil
+}
+
+// Validate reports the first setting that cannot be played.
+func (c Config) Validate() error {
+	if err := c.Rules.Validate(); err != nil {
+		return err
+	}
+	if c.TurnTimeout < 0 {
+		return fmt.Errorf("turn timeout must not be negative, got %v", c.TurnTimeout)
+	}
+	if err := c.TimeoutPolicy.Validate(); err != nil {
+		return fmt.Errorf("timeout policy: %w", err)
+	}
+	if c.AwayAfter < 0 {
+		return fmt.Errorf("away after must not be negative, got %d", c.AwayAfter)
+	}
+	if c.LobbyWait < 0 {
+		return fmt.Errorf("lobby wait must not be negative, got %v", c.LobbyWait)
+	}
+	if c.ReconnectGrace < 0 {
+		return fmt.Errorf("reconnect grace must not be negative, got %v", c.ReconnectGrace)
+	}
+	if c.ShutdownWait < 0 {
+		return fmt.Errorf("shutdown wait must not be negative, got %v", c.ShutdownWait)
+	}
+	if c.MaxTables < 1 {
+		return fmt.Errorf("the lobby must hold at least one table, got %d", c.MaxTables)
+	}
+	return nil
+}
+
+// decodeStrict unmarshals JSON, rejecting fields it does not know so that a
+// misspelt setting is reported instead of silently ignored.
+func decodeStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func setInt(dst *int, src *int) {
+	if src != nil {
+		*dst = *src
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+)
+
+func TestParseDefaultsMissingFields(t *testing.T) {
+	cfg, err := Parse([]byte(`{"target_score": 30, "turn_timeout": "45s", "timeout_policy": "bot", "away_after": 2, "max_tables": 4, "reconnect_grace": "2m", "shutdown_wait": "90s", "save_dir": ""}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := Default()
+	want.Rules.TargetScore = 30
+	want.TurnTimeout = 45 * time.Second
+	want.TimeoutPolicy = game.FallbackBot
+	want.AwayAfter = 2
+	want.MaxTables = 4
+	want.ReconnectGrace = 2 * time.Minute
+	want.ShutdownWait = 90 * time.Second
+	want.SaveDir = ""
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Parse() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestParseScoring(t *testing.T) {
+	cfg, err := Parse([]byte(`{"scoring": "classic"}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cfg.Rules.Scoring != game.ClassicScoring() {
+		t.Errorf("expected the classic preset, got %+v", cfg.Rules.Scoring)
+	}
+
+	cfg, err = Parse([]byte(`{"scoring": {"name": "classic", "last_trick": 10}}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := game.ClassicScoring()
+	want.LastTrick = 10
+	if cfg.Rules.Scoring != want {
+		t.Errorf("expected classic with a 10 point last trick, got %+v", cfg.Rules.Scoring)
+	}
+
+	cfg, err = Parse([]byte(`{"scoring": {"four_of_a_kind": 10}}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cfg.Rules.Scoring.Name != "custom" || cfg.Rules.Scoring.FourOfAKind != 10 || cfg.Rules.Scoring.Pair != 1 {
+		t.Errorf("expected a custom table based on the default, got %+v", cfg.Rules.Scoring)
+	}
+}
+
+func TestParseTrickBonuses(t *testing.T) {
+	cfg, err := Parse([]byte(`{"trick_bonuses": [{"hook": "last_trick_deuce", "points": 10}, {"hook": "last_trick_ace_lead", "points": -5}]}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []game.TrickBonus{{Hook: "last_trick_deuce", Points: 10}, {Hook: "last_trick_ace_lead", Points: -5}}
+	if !reflect.DeepEqual(cfg.Rules.TrickBonuses, want) {
+		t.Errorf("TrickBonuses = %+v, want %+v", cfg.Rules.TrickBonuses, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "Syntax", data: `{"target_score": }`, want: "invalid character"},
+		{name: "UnknownField", data: `{"target_scor": 40}`, want: "target_scor"},
+		{name: "UnknownPreset", data: `{"scoring": "vegas"}`, want: "vegas"},
+		{name: "UnknownScoringField", data: `{"scoring": {"pairs": 2}}`, want: "pairs"},
+		{name: "BadScoring", data: `{"scoring": {"flush": 1}}`, want: "Flush"},
+		{name: "BadTiePolicy", data: `{"tie_policy": "coin"}`, want: "coin"},
+		{name: "TooManyPlayers", data: `{"max_players": 9}`, want: "max players"},
+		{name: "BadEndCondition", data: `{"end_condition": "sudden"}`, want: "sudden"},
+		{name: "RoofAboveTarget", data: `{"end_condition": "roof", "roof": 50}`, want: "roof"},
+		{name: "NoExchanges", data: `{"exchanges": 0}`, want: "exchanges"},
+		{name: "UnknownTrickHook", data: `{"trick_bonuses": [{"hook": "last_trick_seven", "points": 5}]}`, want: "last_trick_seven"},
+		{name: "BadDuration", data: `{"turn_timeout": "soon"}`, want: "soon"},
+		{name: "NegativeTimeout", data: `{"turn_timeout": "-1s"}`, want: "turn timeout"},
+		{name: "BadTimeoutPolicy", data: `{"timeout_policy": "wait"}`, want: "wait"},
+		{name: "NegativeAwayAfter", data: `{"away_after": -1}`, want: "away after"},
+		{name: "NoTables", data: `{"max_tables": 0}`, want: "table"},
+		{name: "NegativeGrace", data: `{"reconnect_grace": "-5s"}`, want: "reconnect grace"},
+		{name: "NegativeShutdownWait", data: `{"shutdown_wait": "-1m"}`, want: "shutdown wait"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%s) error = %v, want it to mention %q", tt.data, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadNamesTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`{"exchanges": -1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("Load() error = %v, want it to name %s", err, path)
+	}
+}
+
+func TestExampleFileIsValid(t *testing.T) {
+	if _, err := Load(filepath.Join("..", "..", "rules.example.json")); err != nil {
+		t.Errorf("rules.example.json: %v", err)
+	}
+}
@@ -2,17 +2,24 @@ package deck
 
 import (
 	"math/rand"
-	"time"
 
 	"github.com/antongollbo123/chicago-poker/pkg/cards"
 )
 
+// Size is the number of cards in a full deck.
+const Size = 52
+
 type Deck struct {
 	cards    []cards.Card
+	discards []cards.Card
 	NumCards int
+	rng      *rand.Rand
 }
 
-func NewDeck() *Deck {
+// NewDeck creates an ordered 52-card deck that shuffles with randomness from
+// src. A nil src uses a cryptographically secure source, which is what real
+// tables should use; pass a seeded source to get a reproducible order.
+func NewDeck(src rand.Source) *Deck {
 	suits := []cards.Suit{cards.Hearts, cards.Spades, cards.Clubs, cards.Diamonds}
 	ranks := []cards.Rank{cards.Two, cards.Three, cards.Four, cards.Five, cards.Six, cards.Seven, cards.Eight, cards.Nine, cards.Ten, cards.Jack, cards.Queen, cards.King, cards.Ace}
 
@@ -26,23 +33,37 @@ func NewDeck() *Deck {
 			cardCount++
 		}
 	}
-	return &Deck{cards: deck, NumCards: cardCount}
+	if src == nil {
+		src = cryptoSource{}
+	}
+	return &Deck{cards: deck, NumCards: cardCount, rng: rand.New(src)}
 }
 
-func (d *Deck) Shuffle() {
-	rand.Seed(time.Now().UnixNano())
+// NewSeededDeck creates a deck whose shuffles are fully determined by seed.
+func NewSeededDeck(seed int64) *Deck {
+	return NewDeck(rand.NewSource(seed))
+}
 
-	rand.Shuffle(len(d.cards), func(i, j int) {
+func (d *Deck) Shuffle() {
+	d.rng.Shuffle(len(d.cards), func(i, j int) {
 		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
 	})
 }
 
+// Peek returns the next numCards cards without drawing them.
+func (d *Deck) Peek(numCards int) []cards.Card {
+	if numCards > len(d.cards) {
+		numCards = len(d.cards)
+	}
+	return append([]cards.Card{}, d.cards[:numCards]...)
+}
+
 func (d *Deck) Draw() (cards.Card, bool) {
 	if len(d.cards) == 0 {
 		return cards.Card{}, false
 	}
 	card := d.cards[0]
-	d.cards = d.cards[1:] // TODO: Add rest deck?
+	d.cards = d.cards[1:]
 	d.NumCards--
 	return card, true
 }
@@ -58,3 +79,23 @@ func (d *Deck) DrawMultiple(numCards int) []cards.Card {
 	}
 	return drawnCards
 }
+
+// Discard puts cards face down on the discard pile.
+func (d *Deck) Discard(discarded ...cards.Card) {
+	d.discards = append(d.discards, discarded...)
+}
+
+// NumDiscards returns the number of cards on the discard pile.
+func (d *Deck) NumDiscards() int {
+	return len(d.discards)
+}
+
+// Reshuffle shuffles the discard pile and puts it under the remaining stock.
+func (d *Deck) Reshuffle() {
+	d.rng.Shuffle(len(d.discards), func(i, j int) {
+		d.discards[i], d.discards[j] = d.discards[j], d.discards[i]
+	})
+	d.cards = append(d.cards, d.discards...)
+	d.NumCards += len(d.discards)
+	d.discards = nil
+}
@@ -2,13 +2,14 @@ package deck
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/antongollbo123/chicago-poker/pkg/cards"
 )
 
 func TestNewDeck(t *testing.T) {
-	deck := NewDeck()
+	deck := NewDeck(nil)
 
 	if deck.NumCards != 52 {
 		t.Errorf("expected 52 cards, got %d", deck.NumCards)
@@ -29,7 +30,7 @@ func TestNewDeck(t *testing.T) {
 }
 
 func TestShuffle(t *testing.T) {
-	deck := NewDeck()
+	deck := NewSeededDeck(1)
 	initialOrder := make([]cards.Card, 52)
 	copy(initialOrder, deck.cards)
 
@@ -49,7 +50,7 @@ func TestShuffle(t *testing.T) {
 }
 
 func TestDraw(t *testing.T) {
-	deck := NewDeck()
+	deck := NewDeck(nil)
 	initialNumCards := deck.NumCards
 
 	card, ok := deck.Draw()
@@ -67,7 +68,7 @@ func TestDraw(t *testing.T) {
 }
 
 func TestDrawMultiple(t *testing.T) {
-	deck := NewDeck()
+	deck := NewDeck(nil)
 	initialNumCards := deck.NumCards
 
 	numToDraw := 5
@@ -83,7 +84,7 @@ func TestDrawMultiple(t *testing.T) {
 }
 
 func TestDrawMultipleNotEnoughCards(t *testing.T) {
-	deck := NewDeck()
+	deck := NewDeck(nil)
 	numToDraw := 60 // More than the number of cards in the deck
 
 	drawnCards := deck.DrawMultiple(numToDraw)
@@ -96,3 +97,100 @@ func TestDrawMultipleNotEnoughCards(t *testing.T) {
 		t.Errorf("expected 0 cards left in the deck, got %d", deck.NumCards)
 	}
 }
+
+func TestSeededShuffleIsDeterministic(t *testing.T) {
+	deck1 := NewSeededDeck(42)
+	deck2 := NewDeck(rand.NewSource(42))
+	deck1.Shuffle()
+	deck2.Shuffle()
+
+	for i := range deck1.cards {
+		if deck1.cards[i] != deck2.cards[i] {
+			t.Fatalf("expected identical order for the same seed, differs at %d: %v != %v", i, deck1.cards[i], deck2.cards[i])
+		}
+	}
+}
+
+func TestKeyedSourceUsesTheWholeKey(t *testing.T) {
+	var key, other Key
+	other[len(other)-1] = 1 // beyond any 31 or 63 bit seed
+	order := func(key Key) []cards.Card {
+		deck := NewDeck(NewKeyedSource(key))
+		deck.Shuffle()
+		return deck.cards
+	}
+
+	if fmt.Sprint(order(key)) != fmt.Sprint(order(key)) {
+		t.Errorf("expected identical order for the same key")
+	}
+	if fmt.Sprint(order(key)) == fmt.Sprint(order(other)) {
+		t.Errorf("expected keys differing in their last byte to shuffle differently")
+	}
+}
+
+func TestPeek(t *testing.T) {
+	deck := NewSeededDeck(7)
+	deck.Shuffle()
+
+	peeked := deck.Peek(3)
+	if deck.NumCards != 52 {
+		t.Errorf("expected Peek to leave 52 cards, got %d", deck.NumCards)
+	}
+	drawn := deck.DrawMultiple(3)
+	for i := range peeked {
+		if peeked[i] != drawn[i] {
+			t.Errorf("expected peeked card %v to be drawn, got %v", peeked[i], drawn[i])
+		}
+	}
+	if len(deck.Peek(60)) != 49 {
+		t.Errorf("expected Peek to be capped at the remaining cards")
+	}
+}
+
+func TestDefaultSourceIsUnseeded(t *testing.T) {
+	deck1 := NewDeck(nil)
+	deck2 := NewDeck(nil)
+	deck1.Shuffle()
+	deck2.Shuffle()
+
+	same := true
+	for i := range deck1.cards {
+		if deck1.cards[i] != deck2.cards[i] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Errorf("expected two decks with the default source to shuffle differently")
+	}
+}
+
+func TestReshuffleDiscards(t *testing.T) {
+	deck := NewSeededDeck(3)
+	deck.Shuffle()
+	hand := deck.DrawMultiple(50)
+	deck.Discard(hand[:4]...)
+
+	if deck.NumDiscards() != 4 {
+		t.Errorf("expected 4 discards, got %d", deck.NumDiscards())
+	}
+
+	deck.Reshuffle()
+
+	if deck.NumCards != 6 || deck.NumDiscards() != 0 {
+		t.Errorf("expected 6 cards in stock and none discarded, got %d and %d", deck.NumCards, deck.NumDiscards())
+	}
+	drawn := deck.DrawMultiple(6)
+	if len(drawn) != 6 {
+		t.Fatalf("expected to draw 6 cards, got %d", len(drawn))
+	}
+	reshuffled := make(map[cards.Card]bool)
+	for _, card := range drawn[2:] {
+		reshuffled[card] = true
+	}
+	for _, card := range hand[:4] {
+		if !reshuffled[card] {
+			t.Errorf("expected discarded card %v under the remaining stock", card)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package deck
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// cryptoSource is a math/rand source backed by crypto/rand. It cannot be
+// seeded, so decks using it are unpredictable and unreproducible.
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic("deck: reading crypto/rand failed: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+func (s cryptoSource) Int63() int64 {
+	return int64(s.Uint64() & (1<<63 - 1))
+}
+
+func (cryptoSource) Seed(int64) {}
+
+// Key seeds a keyed source. A math/rand seed is reduced to 31 bits, few
+// enough to find from the cards of one hand; a key cannot be.
+type Key [32]byte
+
+// RandomKey returns a key drawn from crypto/rand, for games that must be
+// unpredictable at the table yet replayable afterwards.
+func RandomKey() Key {
+	var key Key
+	if _, err := crand.Read(key[:]); err != nil {
+		panic("deck: reading crypto/rand failed: " + err.Error())
+	}
+	return key
+}
+
+func (k Key) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(k[:])), nil
+}
+
+func (k *Key) UnmarshalText(text []byte) error {
+	if hex.DecodedLen(len(text)) != len(k) {
+		return fmt.Errorf("deck: a key is %d hex digits, got %d", 2*len(k), len(text))
+	}
+	_, err := hex.Decode(k[:], text)
+	return err
+}
+
+// keyedSource is a math/rand source backed by ChaCha8, fully determined by
+// its key.
+type keyedSource struct {
+	*randv2.ChaCha8
+}
+
+// NewKeyedSource returns a source whose output is fully determined by key.
+// Decks sharing it shuffle from one stream, in the order they shuffle.
+func NewKeyedSource(key Key) rand.Source {
+	return keyedSource{randv2.NewChaCha8(key)}
+}
+
+func (s keyedSource) Int63() int64 {
+	return int64(s.Uint64() & (1<<63 - 1))
+}
+
+func (keyedSource) Seed(int64) {}
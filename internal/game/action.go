@@ -0,0 +1,54 @@
+package game
+
+type ActionType string
+
+const (
+	ActionToss     ActionType = "toss"
+	ActionStandPat ActionType = "stand_pat"
+	ActionPlay     ActionType = "play"
+	ActionDeclare  ActionType = "declare"
+	ActionPass     ActionType = "pass"
+	ActionClaim    ActionType = "claim"
+)
+
+// Action is a move made by a player. Cards holds hand indices: the cards to
+// toss in an exchange, or the single card to play in a trick. Rank is the
+// hand claimed at a showdown.
+type Action struct {
+	Type   ActionType `json:"type"`
+	Player int        `json:"player"`
+	Cards  []int      `json:"cards,omitempty"`
+	Rank   HandRank   `json:"rank,omitempty"`
+}
+
+// Toss discards the cards at the given hand indices and redraws as many. At
+// least one card must be tossed; use StandPat to keep the hand.
+func Toss(playerIndex int, indices ...int) Action {
+	return Action{Type: ActionToss, Player: playerIndex, Cards: indices}
+}
+
+// StandPat keeps the whole hand for this exchange.
+func StandPat(playerIndex int) Action {
+	return Action{Type: ActionStandPat, Player: playerIndex}
+}
+
+// Play puts the card at the given hand index into the current trick.
+func Play(playerIndex int, index int) Action {
+	return Action{Type: ActionPlay, Player: playerIndex, Cards: []int{index}}
+}
+
+// Declare announces Chicago, a claim to win all five tricks, before the first
+// trick is led.
+func Declare(playerIndex int) Action {
+	return Action{Type: ActionDeclare, Player: playerIndex}
+}
+
+// Pass declines to claim a hand at a showdown or to declare Chicago.
+func Pass(playerIndex int) Action {
+	return Action{Type: ActionPass, Player: playerIndex}
+}
+
+// Claim announces the hand category held at a showdown.
+func Claim(playerIndex int, rank HandRank) Action {
+	return Action{Type: ActionClaim, Player: playerIndex, Rank: rank}
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+// Fallback decides the move made for a player who does not make one
+// themselves, such as when their time to act runs out.
+type Fallback string
+
+const (
+	// FallbackAuto stands pat in exchanges, passes at showdowns and on
+	// Chicago, and plays the lowest legal card in tricks.
+	FallbackAuto Fallback = "auto"
+	// FallbackBot lets BotAction play for the player.
+	FallbackBot Fallback = "bot"
+)
+
+// Validate reports whether the fallback is one the game knows.
+func (f Fallback) Validate() error {
+	switch f {
+	case FallbackAuto, FallbackBot:
+		return nil
+	}
+	return fmt.Errorf("unknown fallback %q, expected %q or %q", f, FallbackAuto, FallbackBot)
+}
+
+// Action returns the move the fallback makes for the player whose turn it is.
+func (f Fallback) Action(g *Game, playerIndex int) Action {
+	if f == FallbackBot {
+		return BotAction(g, playerIndex)
+	}
+	switch g.Stage {
+	case Showdown, Chicago:
+		return Pass(playerIndex)
+	case Trick:
+		return Play(playerIndex, lowestCard(g.Players[playerIndex].Hand, g.LegalPlays(playerIndex)))
+	}
+	return StandPat(playerIndex)
+}
+
+// BotAction picks a move for the player whose turn it is with a simple
+// strategy: keep the cards that make up the hand and toss the rest (all but
+// the highest card of a hand with nothing in it), claim exactly what it
+// holds, never declare Chicago, and in a trick take it with the lowest card
+// that wins or else throw the lowest legal card.
+func BotAction(g *Game, playerIndex int) Action {
+	hand := g.Players[playerIndex].Hand
+	switch g.Stage {
+	case Showdown:
+		rank := EvaluateHand(hand).Rank
+		if rank == HighCard || rank < g.BestClaim() {
+			return Pass(playerIndex)
+		}
+		return Claim(playerIndex, rank)
+	case Chicago:
+		return Pass(playerIndex)
+	case Trick:
+		return Play(playerIndex, g.botTrickPlay(playerIndex))
+	}
+
+	eval := EvaluateHand(hand)
+	if eval.Rank >= Straight {
+		return StandPat(playerIndex)
+	}
+	toss := eval.Kickers
+	if eval.Rank == HighCard {
+		// Nothing to keep but the highest card
+		toss = eval.ScoreCards[1:]
+	}
+	indices := []int{}
+	for i, card := range hand {
+		if containsCard(toss, card) {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == 0 {
+		return StandPat(playerIndex)
+	}
+	return Toss(playerIndex, indices...)
+}
+
+// botTrickPlay returns the hand index of the lowest legal card that would win
+// the current trick so far, or of the lowest legal card if none would.
+func (g *Game) botTrickPlay(playerIndex int) int {
+	hand := g.Players[playerIndex].Hand
+	legal := g.LegalPlays(playerIndex)
+	if g.acted == 0 {
+		return lowestCard(hand, legal)
+	}
+	winning := g.trick[findWinner(g.trick, g.lead)]
+	winners := []int{}
+	for _, i := range legal {
+		if hand[i].Suit == winning.Suit && hand[i].Rank > winning.Rank {
+			winners = append(winners, i)
+		}
+	}
+	if len(winners) > 0 {
+		return lowestCard(hand, winners)
+	}
+	return lowestCard(hand, legal)
+}
+
+// lowestCard returns the index, out of the given hand indices, of the lowest
+// card by rank and then suit.
+func lowestCard(hand []cards.Card, indices []int) int {
+	lowest := indices[0]
+	for _, i := range indices[1:] {
+		if higherCard(hand[lowest], hand[i]) {
+			lowest = i
+		}
+	}
+	return lowest
+}
+
+func containsCard(list []cards.Card, card cards.Card) bool {
+	for _, c := range list {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
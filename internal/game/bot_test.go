@@ -0,0 +1,88 @@
+package game_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+func TestBotsPlayAGameToTheEnd(t *testing.T) {
+	for _, announce := range []bool{false, true} {
+		players := []*player.Player{player.NewPlayer("Ada"), player.NewPlayer("Bo"), player.NewPlayer("Cy")}
+		g := game.NewSeededGame(players, 7)
+		g.Rules.Announce = announce
+		if _, err := g.Start(); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		for moves := 0; g.Stage != game.Over; moves++ {
+			if moves > 10000 {
+				t.Fatalf("announce=%v: game did not end", announce)
+			}
+			mustApply(t, g, game.BotAction(g, g.Turn()))
+		}
+	}
+}
+
+func TestBotKeepsWhatMakesItsHand(t *testing.T) {
+	g := newStartedGame(t, "Ada", "Bo")
+	seat := g.Turn()
+	g.Players[seat].Hand = []cards.Card{
+		{Suit: cards.Clubs, Rank: cards.Four},
+		{Suit: cards.Hearts, Rank: cards.Nine},
+		{Suit: cards.Spades, Rank: cards.King},
+		{Suit: cards.Diamonds, Rank: cards.Nine},
+		{Suit: cards.Clubs, Rank: cards.Two},
+	}
+	want := game.Toss(seat, 0, 2, 4)
+	if got := game.BotAction(g, seat); !reflect.DeepEqual(got, want) {
+		t.Errorf("BotAction() with a pair = %+v, want %+v", got, want)
+	}
+
+	g.Players[seat].Hand[3] = cards.Card{Suit: cards.Diamonds, Rank: cards.Ten}
+	want = game.Toss(seat, 0, 1, 3, 4)
+	if got := game.BotAction(g, seat); !reflect.DeepEqual(got, want) {
+		t.Errorf("BotAction() with nothing = %+v, want %+v", got, want)
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	g := newStartedGame(t, "Ada", "Bo")
+	if got := game.FallbackAuto.Action(g, g.Turn()); !reflect.DeepEqual(got, game.StandPat(g.Turn())) {
+		t.Errorf("auto fallback in an exchange = %+v, want stand pat", got)
+	}
+
+	standPatToTricks(t, g)
+	seat := g.Turn()
+	g.Players[seat].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Queen},
+		{Suit: cards.Spades, Rank: cards.Three},
+		{Suit: cards.Clubs, Rank: cards.Three},
+		{Suit: cards.Hearts, Rank: cards.Ace},
+		{Suit: cards.Diamonds, Rank: cards.Eight},
+	}
+	if got := game.FallbackAuto.Action(g, seat); !reflect.DeepEqual(got, game.Play(seat, 2)) {
+		t.Errorf("auto fallback leading a trick = %+v, want the three of clubs", got)
+	}
+	mustApply(t, g, game.Play(seat, 0))
+
+	// Holding the lead suit, the bot takes the trick as cheaply as it can
+	other := g.Turn()
+	g.Players[other].Hand = []cards.Card{
+		{Suit: cards.Spades, Rank: cards.Ace},
+		{Suit: cards.Hearts, Rank: cards.Two},
+		{Suit: cards.Hearts, Rank: cards.King},
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Hearts, Rank: cards.Ace},
+	}
+	if got := game.FallbackBot.Action(g, other); !reflect.DeepEqual(got, game.Play(other, 2)) {
+		t.Errorf("bot following a queen = %+v, want the king of hearts", got)
+	}
+	g.Players[other].Hand[2] = cards.Card{Suit: cards.Clubs, Rank: cards.King}
+	g.Players[other].Hand[4] = cards.Card{Suit: cards.Diamonds, Rank: cards.Ace}
+	if got := game.FallbackBot.Action(g, other); !reflect.DeepEqual(got, game.Play(other, 1)) {
+		t.Errorf("bot unable to win = %+v, want the two of hearts", got)
+	}
+}
@@ -0,0 +1,69 @@
+package game
+
+import "errors"
+
+var (
+	ErrInvalidClaim = errors.New("that hand cannot be claimed")
+	ErrClaimTooLow  = errors.New("a claim must be at least as good as the best claim so far")
+	ErrUnderClaim   = errors.New("you hold a better hand than that; claim it or pass")
+)
+
+// With Rules.Announce, every exchange ends in the Showdown stage: in turn
+// order each player claims the hand category they hold or passes. A claim
+// must match or beat the best claim so far and is checked against the
+// player's cards: claiming less than they hold is refused, while claiming
+// more is a false claim that costs ScoringRules.FalseClaim and is void.
+// Only the hands claimed in the best category are compared, and only the
+// winner's scoring cards are revealed.
+
+// BestClaim returns the best hand claimed so far at the current showdown, or
+// HighCard if nobody has claimed one.
+func (g *Game) BestClaim() HandRank {
+	best := HighCard
+	for _, claim := range g.claims {
+		if claim > best {
+			best = claim
+		}
+	}
+	return best
+}
+
+func (g *Game) claim(a Action) error {
+	if g.Stage != Showdown {
+		return ErrWrongStage
+	}
+	if a.Rank < Pair || a.Rank > RoyalStraightFlush {
+		return ErrInvalidClaim
+	}
+	if a.Rank < g.BestClaim() {
+		return ErrClaimTooLow
+	}
+
+	held := EvaluateHand(g.Players[a.Player].Hand).Rank
+	if a.Rank < held {
+		return ErrUnderClaim
+	}
+
+	g.emit(Event{Type: EventClaim, Player: a.Player, Rank: a.Rank})
+	if a.Rank > held {
+		g.emit(Event{Type: EventFalseClaim, Player: a.Player, Rank: a.Rank, Points: -g.Rules.Scoring.FalseClaim})
+	}
+	g.endShowdownTurn()
+	return nil
+}
+
+// endShowdownTurn compares the best claims once everyone has claimed or
+// passed.
+func (g *Game) endShowdownTurn() {
+	if !g.roundComplete() {
+		return
+	}
+	best := g.BestClaim()
+	claimants := []int{}
+	for _, player := range g.playOrder() {
+		if best != HighCard && g.claims[player] == best {
+			claimants = append(claimants, player)
+		}
+	}
+	g.showdown(g.rankPlayers(claimants))
+}
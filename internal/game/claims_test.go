@@ -0,0 +1,138 @@
+package game_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+// newAnnouncingGame starts a three player game in which hands are announced
+// and player 0 deals, so player 1 acts first.
+func newAnnouncingGame(t *testing.T) *game.Game {
+	t.Helper()
+	g := game.NewGame([]*player.Player{player.NewPlayer("Alice"), player.NewPlayer("Bob"), player.NewPlayer("Carol")})
+	g.Rules.Announce = true
+	if _, err := g.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.King},
+		{Suit: cards.Spades, Rank: cards.King},
+		{Suit: cards.Clubs, Rank: cards.Two},
+		{Suit: cards.Spades, Rank: cards.Seven},
+		{Suit: cards.Hearts, Rank: cards.Nine},
+	}
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Spades, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Nine},
+		{Suit: cards.Spades, Rank: cards.Nine},
+		{Suit: cards.Hearts, Rank: cards.Two},
+	}
+	g.Players[2].Hand = []cards.Card{
+		{Suit: cards.Diamonds, Rank: cards.Three},
+		{Suit: cards.Diamonds, Rank: cards.Six},
+		{Suit: cards.Diamonds, Rank: cards.Eight},
+		{Suit: cards.Diamonds, Rank: cards.Jack},
+		{Suit: cards.Diamonds, Rank: cards.Ace},
+	}
+	for _, p := range g.Players {
+		p.Score = 0
+	}
+	for g.Stage == game.Exchange {
+		mustApply(t, g, game.StandPat(g.Turn()))
+	}
+	if g.Stage != game.Showdown || g.Turn() != 1 {
+		t.Fatalf("expected player 1 to open the showdown, got stage %s turn %d", g.Stage, g.Turn())
+	}
+	return g
+}
+
+func TestClaimsAreVerifiedAndOnlyTheWinnerShows(t *testing.T) {
+	g := newAnnouncingGame(t)
+	penalty := g.Rules.Scoring.FalseClaim
+
+	if _, err := g.Apply(game.Claim(1, game.HighCard)); !errors.Is(err, game.ErrInvalidClaim) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrInvalidClaim)
+	}
+	// Bob holds two pair and may not claim less
+	if _, err := g.Apply(game.Claim(1, game.Pair)); !errors.Is(err, game.ErrUnderClaim) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrUnderClaim)
+	}
+	if g.Players[1].Score != 0 || g.Turn() != 1 {
+		t.Errorf("expected the under-claim to change nothing, score %d turn %d", g.Players[1].Score, g.Turn())
+	}
+	var events []game.Event
+	events = append(events, mustApply(t, g, game.Claim(1, game.TwoPair))...)
+	if _, err := g.Apply(game.Claim(2, game.Pair)); !errors.Is(err, game.ErrClaimTooLow) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrClaimTooLow)
+	}
+	// Carol holds a flush but claims more, Alice holds a pair but claims two
+	events = append(events, mustApply(t, g, game.Claim(2, game.FourOfAKind))...)
+	if g.BestClaim() != game.TwoPair {
+		t.Errorf("expected the false claim to be void, best claim is %v", g.BestClaim())
+	}
+	events = append(events, mustApply(t, g, game.Claim(0, game.TwoPair))...)
+
+	falseClaims := map[int]bool{}
+	var scored []game.Event
+	for _, e := range events {
+		switch e.Type {
+		case game.EventFalseClaim:
+			falseClaims[e.Player] = true
+		case game.EventHandScored:
+			scored = append(scored, e)
+		case game.EventClaim:
+			if len(e.Cards) != 0 {
+				t.Errorf("expected claims to show no cards, got %+v", e)
+			}
+		}
+	}
+	if len(falseClaims) != 2 || !falseClaims[0] || !falseClaims[2] {
+		t.Errorf("expected the claims of players 0 and 2 to be false, got %v", falseClaims)
+	}
+	if len(scored) != 1 || scored[0].Player != 1 || scored[0].Rank != game.TwoPair || len(scored[0].Cards) != 4 {
+		t.Fatalf("expected Bob to show and score his two pair, got %+v", scored)
+	}
+	want := []int{-penalty, g.Rules.Scoring.TwoPair, -penalty}
+	for i, p := range g.Players {
+		if p.Score != want[i] {
+			t.Errorf("player %d scored %d, want %d", i, p.Score, want[i])
+		}
+	}
+	if g.Stage != game.Exchange || g.Round != 1 || g.Turn() != 1 {
+		t.Errorf("expected the second exchange to open, got stage %s round %d turn %d", g.Stage, g.Round, g.Turn())
+	}
+}
+
+func TestEveryoneWithholdingScoresNothing(t *testing.T) {
+	g := newAnnouncingGame(t)
+
+	for g.Stage == game.Showdown {
+		for _, e := range mustApply(t, g, game.Pass(g.Turn())) {
+			if e.Type == game.EventHandScored || len(e.Cards) != 0 {
+				t.Errorf("expected nothing to be scored or shown, got %+v", e)
+			}
+		}
+	}
+	for _, p := range g.Players {
+		if p.Score != 0 {
+			t.Errorf("player %s scored %d, want 0", p.Name, p.Score)
+		}
+	}
+
+	// Every exchange ends in a showdown, the last one leads to Chicago
+	for g.Stage == game.Exchange || g.Stage == game.Showdown {
+		if g.Stage == game.Exchange {
+			mustApply(t, g, game.StandPat(g.Turn()))
+		} else {
+			mustApply(t, g, game.Pass(g.Turn()))
+		}
+	}
+	if g.Stage != game.Chicago || g.Round != g.Rules.Exchanges {
+		t.Errorf("expected chicago after %d exchanges, got stage %s round %d", g.Rules.Exchanges, g.Stage, g.Round)
+	}
+}
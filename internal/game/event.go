@@ -0,0 +1,122 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+type EventType string
+
+const (
+	EventNewHand       EventType = "new_hand"
+	EventDeal          EventType = "deal"
+	EventStage         EventType = "stage"
+	EventReshuffle     EventType = "reshuffle"
+	EventToss          EventType = "toss"
+	EventDraw          EventType = "draw"
+	EventStandPat      EventType = "stand_pat"
+	EventClaim         EventType = "claim"
+	EventFalseClaim    EventType = "false_claim"
+	EventShowdown      EventType = "showdown"
+	EventHandScored    EventType = "hand_scored"
+	EventDeclare       EventType = "declare"
+	EventPass          EventType = "pass"
+	EventChicagoScored EventType = "chicago_scored"
+	EventTrickPlay     EventType = "trick_play"
+	EventTrickWon      EventType = "trick_won"
+	EventTrickScored   EventType = "trick_scored"
+	EventBonusScored   EventType = "bonus_scored"
+	EventBust          EventType = "bust"
+	EventGameOver      EventType = "game_over"
+)
+
+// Event records something that happened in the game. Player is -1 for events
+// that concern the whole table.
+type Event struct {
+	Type   EventType    `json:"type"`
+	Player int          `json:"player"`
+	Cards  []cards.Card `json:"cards,omitempty"`
+	Points int          `json:"points,omitempty"`
+	Rank   HandRank     `json:"rank,omitempty"`
+	Stage  Stage        `json:"stage,omitempty"`
+	Hook   string       `json:"hook,omitempty"` // trick hook that scored a bonus
+}
+
+// Private reports whether the event's cards are only meant for its player.
+func (e Event) Private() bool {
+	return e.Type == EventDeal || e.Type == EventToss || e.Type == EventDraw
+}
+
+// Describe renders the public, human readable form of an event. Cards of
+// private events are never included.
+func (g *Game) Describe(e Event) string {
+	name := ""
+	if e.Player >= 0 && e.Player < len(g.Players) {
+		name = g.Players[e.Player].Name
+	}
+	switch e.Type {
+	case EventNewHand:
+		return fmt.Sprintf("Player %s deals a new hand", name)
+	case EventDeal:
+		return fmt.Sprintf("Player %s was dealt %d cards", name, len(e.Cards))
+	case EventStage:
+		switch e.Stage {
+		case Trick:
+			return "TRICK ROUND!"
+		case Showdown:
+			return "Announce your hand, or pass."
+		case Chicago:
+			return "Declare Chicago now if you can take all five tricks."
+		case Exchange:
+			return fmt.Sprintf("Exchanges: up to %d, with a showdown after each. Toss cards or stand pat.", g.Rules.Exchanges)
+		}
+		return fmt.Sprintf("Starting %s round", e.Stage)
+	case EventReshuffle:
+		return "The stock ran out: the discard pile has been reshuffled into it"
+	case EventToss:
+		return fmt.Sprintf("Player %s tossed %d cards", name, len(e.Cards))
+	case EventDraw:
+		return fmt.Sprintf("Player %s drew %d cards", name, len(e.Cards))
+	case EventStandPat:
+		return fmt.Sprintf("Player %s stands pat", name)
+	case EventClaim:
+		return fmt.Sprintf("Player %s claims a %v", name, e.Rank)
+	case EventFalseClaim:
+		return fmt.Sprintf("Player %s does not hold a %v and loses %d points", name, e.Rank, -e.Points)
+	case EventShowdown:
+		return "Showdown!"
+	case EventHandScored:
+		return fmt.Sprintf("Player %s wins the showdown with a %v of %v and gets %d points", name, e.Rank, e.Cards, e.Points)
+	case EventDeclare:
+		return fmt.Sprintf("Player %s declares Chicago and leads the first trick!", name)
+	case EventPass:
+		if e.Stage == Showdown {
+			return fmt.Sprintf("Player %s passes", name)
+		}
+		return fmt.Sprintf("Player %s does not declare Chicago", name)
+	case EventChicagoScored:
+		if e.Points < 0 {
+			return fmt.Sprintf("Player %s loses a trick and fails Chicago: %d points", name, e.Points)
+		}
+		return fmt.Sprintf("Player %s takes every trick and makes Chicago for %d points!", name, e.Points)
+	case EventTrickPlay:
+		return fmt.Sprintf("Player %s played %v", name, e.Cards)
+	case EventTrickWon:
+		return fmt.Sprintf("Player %s wins the trick with %v", name, e.Cards)
+	case EventTrickScored:
+		return fmt.Sprintf("Player %s wins the trick round and gets %d points", name, e.Points)
+	case EventBonusScored:
+		reason := e.Hook
+		if hook, ok := trickHooks[e.Hook]; ok {
+			reason = hook.Description
+		}
+		return fmt.Sprintf("Player %s %s: %+d points", name, reason, e.Points)
+	case EventBust:
+		return fmt.Sprintf("Player %s overshoots %d and falls back %d points", name, g.Rules.TargetScore, -e.Points)
+	case EventGameOver:
+		return fmt.Sprintf("Player %s wins the game with %d points!", name, e.Points)
+	default:
+		return fmt.Sprintf("%s: %v", e.Type, e)
+	}
+}
@@ -1,11 +1,9 @@
 package game
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
-	"reflect"
-	"sort"
+	"math/rand"
 
 	"github.com/antongollbo123/chicago-poker/internal/deck"
 	"github.com/antongollbo123/chicago-poker/internal/player"
@@ -13,178 +11,468 @@ import (
 )
 
 type Stage string
-type Chicago bool
 
+// A hand is dealt once and then goes through Rules.Exchanges exchanges, each
+// followed by a showdown that scores the best hand, in a Showdown stage of
+// its own when players announce their hands. In the Chicago stage the players
+// may, in turn, claim all five tricks, and the same five cards are then
+// played out in the Trick stage.
 const (
-	Poker Stage = "Poker"
-	Trick Stage = "Trick"
+	Lobby    Stage = "Lobby"
+	Exchange Stage = "Exchange"
+	Showdown Stage = "Showdown"
+	Chicago  Stage = "Chicago"
+	Trick    Stage = "Trick"
+	Over     Stage = "Over"
 )
 
 const (
-	TrickWin int = 3
+	HandSize int = 5
 )
 
+var (
+	ErrNotEnoughPlayers = errors.New("not enough players to start the game")
+	ErrGameStarted      = errors.New("game has already started")
+	ErrTableFull        = errors.New("table is full")
+	ErrGameOver         = errors.New("game is over")
+	ErrWrongStage       = errors.New("action is not allowed in this stage")
+	ErrNotYourTurn      = errors.New("it is not your turn")
+	ErrInvalidCard      = errors.New("invalid card index")
+	ErrMustFollowSuit   = errors.New("you must follow the suit")
+	ErrUnknownAction    = errors.New("unknown action")
+	ErrReplayMismatch   = errors.New("event does not match the replayed game")
+)
+
+// Game is the Chicago rules engine. It holds no connections and does no I/O:
+// callers feed it actions through Apply and present the events it returns.
+// Every change to the game once it has started is recorded as an event in an
+// append-only log, so a game can be rebuilt from its seed with Replay.
 type Game struct {
 	Deck    *deck.Deck
 	Players []*player.Player
-	Round   int
+	Rules   Rules
+	Round   int // exchanges completed in the current hand
 	Stage   Stage
+
+	dealer   int           // player dealing the current hand
+	turn     int           // player expected to act next
+	lead     int           // player leading the current trick
+	acted    int           // players who have acted in the current round or trick
+	trick    []cards.Card  // cards played in the current trick, by player
+	tricks   int           // tricks completed in the current hand
+	history  []PlayedTrick // tricks completed in the current hand, in order
+	declarer int           // player who declared Chicago, or -1
+	failed   bool          // the declarer has lost a trick this hand
+	claims   []HandRank    // hand claimed by each player at the showdown
+	seed     int64         // seed every deck of the game is derived from
+	rng      *rand.Rand    // source of per-hand deck seeds
+	key      *deck.Key     // key of a keyed game, which has no seed
+	source   rand.Source   // what every deck of a keyed game shuffles from
+	log      []Event       // every event since the game started
+	events   []Event       // events emitted by the action being applied
 }
 
+// NewGame creates a game shuffled from a random key, so that no one can work
+// out the cards to come from the ones they have seen.
 func NewGame(players []*player.Player) *Game {
-	game := Game{}
-	game.Round = 0
-	game.Stage = Poker // Change back to poker
-	game.Players = players
-	deck := deck.NewDeck()
-	deck.Shuffle()
-	game.Deck = deck
+	return newKeyedGame(players, deck.RandomKey())
+}
+
+// newKeyedGame creates a game whose card order is fully determined by key.
+func newKeyedGame(players []*player.Player, key deck.Key) *Game {
+	g := NewSeededGame(players, 0)
+	g.rng = nil
+	g.key = &key
+	g.source = deck.NewKeyedSource(key)
+	return g
+}
+
+// NewSeededGame creates a game whose card order is fully determined by seed.
+func NewSeededGame(players []*player.Player, seed int64) *Game {
+	game := Game{
+		Players:  players,
+		Rules:    DefaultRules(),
+		Round:    0,
+		Stage:    Lobby,
+		dealer:   -1,
+		declarer: -1,
+		seed:     seed,
+		rng:      rand.New(rand.NewSource(seed)),
+	}
 	return &game
 }
 
-func (g *Game) Deal() {
-	for _, player := range g.Players {
-		cards := g.Deck.DrawMultiple(5)
-		player.Hand = cards
+// Seed returns the seed the game's decks are shuffled from, or zero for a
+// game shuffled from a key.
+func (g *Game) Seed() int64 {
+	return g.seed
+}
+
+// AddPlayer seats a new player. Players can only join before the game starts
+// and while the table has room.
+func (g *Game) AddPlayer(p *player.Player) error {
+	if g.Stage != Lobby {
+		return ErrGameStarted
 	}
+	if len(g.Players) >= g.Rules.MaxPlayers {
+		return ErrTableFull
+	}
+	g.Players = append(g.Players, p)
+	return nil
 }
 
-func (g *Game) TossCards(playerIndex int, indicesToRemove []int) {
-	if playerIndex < 0 || playerIndex >= len(g.Players) {
-		// TODO: Handle invalid player index
-		return
+// RemovePlayer gives up the seat of the player at index before the game
+// starts. Players seated after them move up one seat.
+func (g *Game) RemovePlayer(index int) error {
+	if g.Stage != Lobby {
+		return ErrGameStarted
 	}
+	if index < 0 || index >= len(g.Players) {
+		return fmt.Errorf("no player in seat %d", index)
+	}
+	g.Players = append(g.Players[:index], g.Players[index+1:]...)
+	return nil
+}
 
-	// Sort indicesToRemove in descending order to safely remove cards from hand slice
-	sort.Sort(sort.Reverse(sort.IntSlice(indicesToRemove)))
+// Start deals the first hand and opens the first poker round.
+func (g *Game) Start() ([]Event, error) {
+	if g.Stage != Lobby {
+		return nil, ErrGameStarted
+	}
+	if err := g.Rules.Validate(); err != nil {
+		return nil, err
+	}
+	if len(g.Players) < g.Rules.MinPlayers {
+		return nil, ErrNotEnoughPlayers
+	}
+	if len(g.Players) > g.Rules.MaxPlayers {
+		return nil, ErrTableFull
+	}
+	g.events = nil
+	g.newHand()
+	return g.events, nil
+}
+
+// Dealer returns the index of the player dealing the current hand. Play
+// starts to the dealer's left.
+func (g *Game) Dealer() int {
+	return g.dealer
+}
+
+// Turn returns the index of the player expected to act next.
+func (g *Game) Turn() int {
+	return g.turn
+}
+
+// Lead returns the index of the player leading the current trick.
+func (g *Game) Lead() int {
+	return g.lead
+}
+
+// TrickNumber returns the number of tricks completed in the current hand.
+func (g *Game) TrickNumber() int {
+	return g.tricks
+}
+
+// CurrentTrick returns the cards played so far in the current trick, indexed
+// by player. Players who have not played yet hold the zero card.
+func (g *Game) CurrentTrick() []cards.Card {
+	return append([]cards.Card(nil), g.trick...)
+}
 
-	// Remove cards from player's hand based on indicesToRemove
-	for _, idx := range indicesToRemove {
-		if idx >= 0 && idx < len(g.Players[playerIndex].Hand) {
-			g.Players[playerIndex].Hand = append(g.Players[playerIndex].Hand[:idx], g.Players[playerIndex].Hand[idx+1:]...)
+// TrickHistory returns the tricks completed so far in the current hand.
+func (g *Game) TrickHistory() []PlayedTrick {
+	history := make([]PlayedTrick, len(g.history))
+	for i, trick := range g.history {
+		history[i] = trick
+		history[i].Cards = copyCards(trick.Cards)
+	}
+	return history
+}
+
+// Declarer returns the index of the player who declared Chicago this hand,
+// or -1 if nobody has.
+func (g *Game) Declarer() int {
+	return g.declarer
+}
+
+// Apply validates an action against the rules and the current state, applies
+// it and returns the events it produced. A rejected action leaves the game
+// untouched.
+func (g *Game) Apply(a Action) ([]Event, error) {
+	switch g.Stage {
+	case Lobby:
+		return nil, ErrWrongStage
+	case Over:
+		return nil, ErrGameOver
+	}
+	if a.Player != g.turn {
+		return nil, ErrNotYourTurn
+	}
+
+	g.events = nil
+	var err error
+	switch a.Type {
+	case ActionToss:
+		err = g.toss(a)
+	case ActionStandPat:
+		err = g.standPat(a)
+	case ActionPlay:
+		err = g.play(a)
+	case ActionDeclare:
+		err = g.declare(a)
+	case ActionPass:
+		err = g.pass(a)
+	case ActionClaim:
+		err = g.claim(a)
+	default:
+		err = ErrUnknownAction
+	}
+	if err != nil {
+		return nil, err
+	}
+	return g.events, nil
+}
+
+// emit applies an event to the game and records it. Events emitted by the
+// engine are built from the current state, so applying them cannot fail.
+func (g *Game) emit(e Event) {
+	if err := g.apply(e); err != nil {
+		panic(fmt.Sprintf("game: emitted inconsistent event %+v: %v", e, err))
+	}
+	g.log = append(g.log, e)
+	g.events = append(g.events, e)
+}
+
+func (g *Game) newHand() {
+	// The dealer button moves one seat to the left every hand
+	g.emit(Event{Type: EventNewHand, Player: (g.dealer + 1) % len(g.Players)})
+	g.Deal()
+	g.emit(Event{Type: EventStage, Player: -1, Stage: Exchange})
+}
+
+// roundComplete reports whether everyone has acted in the current round or
+// trick.
+func (g *Game) roundComplete() bool {
+	return g.acted == len(g.Players)
+}
+
+func (g *Game) toss(a Action) error {
+	if g.Stage != Exchange {
+		return ErrWrongStage
+	}
+	hand := g.Players[a.Player].Hand
+	if len(a.Cards) == 0 {
+		return ErrInvalidCard
+	}
+	seen := make(map[int]bool)
+	tossed := []cards.Card{}
+	for _, idx := range a.Cards {
+		if idx < 0 || idx >= len(hand) || seen[idx] {
+			return ErrInvalidCard
 		}
+		seen[idx] = true
+		tossed = append(tossed, hand[idx])
 	}
+
+	// When the stock runs out, the earlier discards are reshuffled into it
+	// before this player discards, so nobody draws back their own cards.
+	if g.Deck.NumCards < len(tossed) {
+		g.emit(Event{Type: EventReshuffle, Player: -1})
+	}
+	g.emit(Event{Type: EventToss, Player: a.Player, Cards: tossed})
+	g.emit(Event{Type: EventDraw, Player: a.Player, Cards: g.Deck.Peek(len(tossed))})
+	g.endExchangeTurn()
+	return nil
 }
 
-func (g *Game) getHighScore() int {
-	highScore := 0
-	for _, player := range g.Players {
-		if player.Score > highScore {
-			highScore = player.Score
+func (g *Game) standPat(a Action) error {
+	if g.Stage != Exchange {
+		return ErrWrongStage
+	}
+	g.emit(Event{Type: EventStandPat, Player: a.Player})
+	g.endExchangeTurn()
+	return nil
+}
+
+// endExchangeTurn holds the showdown once everyone has exchanged or stood
+// pat, opening the Showdown stage if hands are announced.
+func (g *Game) endExchangeTurn() {
+	if !g.roundComplete() {
+		return
+	}
+	if g.Rules.Announce {
+		g.emit(Event{Type: EventStage, Player: -1, Stage: Showdown})
+		return
+	}
+	g.showdown(g.EvaluateHands())
+}
+
+// showdown scores the best of the ranked hands for the exchange just
+// completed and moves on to the next exchange, or to Chicago after the last.
+func (g *Game) showdown(standings []Standing) {
+	g.emit(Event{Type: EventShowdown, Player: -1})
+	for _, best := range ResolveTie(g.Rules.TiePolicy, Winners(standings)) {
+		points := g.Rules.Scoring.HandPoints(best.Evaluation.Rank)
+		// A hand worth nothing is neither scored nor shown: its cards are
+		// still to be played in the tricks
+		if best.Evaluation.Rank == HighCard || points == 0 {
+			continue
+		}
+		over := g.award(Event{
+			Type:   EventHandScored,
+			Player: best.Player,
+			Rank:   best.Evaluation.Rank,
+			Cards:  copyCards(best.Evaluation.ScoreCards),
+			Points: points,
+		})
+		if over {
+			return
 		}
 	}
-	fmt.Println("high score is: ", highScore)
-	return highScore
+	switch {
+	case g.Round >= g.Rules.Exchanges:
+		g.emit(Event{Type: EventStage, Player: -1, Stage: Chicago})
+	case g.Stage == Showdown:
+		g.emit(Event{Type: EventStage, Player: -1, Stage: Exchange})
+	}
 }
 
-func (g *Game) StartGame() {
-	g.Deal()
-	for g.getHighScore() < 50 {
-		switch g.Stage {
-		case Poker:
-			g.PokerRound()
-		case Trick:
-			g.TrickRound()
+// declare claims all five tricks. Only one player can declare a hand: the
+// first to do so in turn order, who then leads the first trick.
+func (g *Game) declare(a Action) error {
+	if g.Stage != Chicago {
+		return ErrWrongStage
+	}
+	g.emit(Event{Type: EventDeclare, Player: a.Player})
+	g.emit(Event{Type: EventStage, Player: -1, Stage: Trick})
+	return nil
+}
+
+// pass declines to claim a hand at a showdown or to declare Chicago. When
+// everyone has passed on Chicago, the player left of the dealer leads the
+// first trick.
+func (g *Game) pass(a Action) error {
+	switch g.Stage {
+	case Showdown:
+		g.emit(Event{Type: EventPass, Player: a.Player, Stage: Showdown})
+		g.endShowdownTurn()
+	case Chicago:
+		g.emit(Event{Type: EventPass, Player: a.Player, Stage: Chicago})
+		if g.roundComplete() {
+			g.emit(Event{Type: EventStage, Player: -1, Stage: Trick})
 		}
+	default:
+		return ErrWrongStage
+	}
+	return nil
+}
+
+func (g *Game) play(a Action) error {
+	if g.Stage != Trick {
+		return ErrWrongStage
+	}
+	hand := g.Players[a.Player].Hand
+	if len(a.Cards) != 1 || a.Cards[0] < 0 || a.Cards[0] >= len(hand) {
+		return ErrInvalidCard
+	}
+	playedCard := hand[a.Cards[0]]
+	if g.acted > 0 && !isValidTrickMove(hand, playedCard, g.trick[g.lead]) {
+		return ErrMustFollowSuit
+	}
+
+	g.emit(Event{Type: EventTrickPlay, Player: a.Player, Cards: []cards.Card{playedCard}})
+	if !g.roundComplete() {
+		return nil
+	}
 
+	winnerIndex := findWinner(g.trick, g.lead)
+	chicagoLost := g.declarer != -1 && !g.failed && winnerIndex != g.declarer
+	g.emit(Event{Type: EventTrickWon, Player: winnerIndex, Cards: []cards.Card{g.trick[winnerIndex]}})
+	if chicagoLost {
+		// The declarer pays as soon as a trick goes to someone else
+		g.emit(Event{Type: EventChicagoScored, Player: g.declarer, Points: -g.Rules.Scoring.Chicago})
 	}
 
+	if g.tricks == HandSize {
+		// A successful Chicago is scored instead of the last trick
+		scored := Event{Type: EventTrickScored, Player: winnerIndex, Points: g.Rules.Scoring.LastTrick}
+		if g.declarer != -1 && !g.failed {
+			scored = Event{Type: EventChicagoScored, Player: g.declarer, Points: g.Rules.Scoring.Chicago}
+		}
+		if g.award(scored) || g.scoreTrickHooks() {
+			return nil
+		}
+		g.newHand()
+	}
+	return nil
 }
 
-func (g *Game) PokerRound() {
-	fmt.Println("Starting Poker Round: ", g.Round+1)
-	scanner := bufio.NewScanner(os.Stdin)
-	for i, player := range g.Players {
-		fmt.Printf("Player %s, your hand is: %v\n", player.Name, player.Hand)
-		fmt.Printf("Enter the indices of the cards you want to toss, separated by spaces: ")
-		scanner.Scan()
-		input := scanner.Text()
-		indicesToRemove := ParseInput(input)
-		fmt.Printf("Player %s is tossing cards: %v\n", player.Name, indicesToRemove)
-		g.TossCards(i, indicesToRemove)
-		// Deal new cards from the deck
-		newCards := g.Deck.DrawMultiple(len(indicesToRemove))
-		g.Players[i].Hand = append(g.Players[i].Hand, newCards...)
-
-		fmt.Printf("Player %s has new hand: %v\n", player.Name, player.Hand)
-	}
-	bestPlayerIndex, bestHandEvaluation := g.EvaluateHands()
-
-	fmt.Printf("Player %s wins the round with a %v of %v and gets %d points\n", g.Players[bestPlayerIndex].Name, bestHandEvaluation.Rank, bestHandEvaluation.ScoreCards, bestHandEvaluation.Score)
-
-	g.Round++
-
-	if g.Round > 2 {
-		g.Stage = Trick
-	}
-
-}
-
-func (g *Game) TrickRound() {
-	fmt.Println("TRICK ROUND!")
-	scanner := bufio.NewScanner(os.Stdin)
-	// Make copies of hands
-	bestPlayerIndex, bestHandEvaluation := g.EvaluateHands()
-
-	leadIndex := 0
-	for trick := 0; trick < 5; trick++ {
-		var leadCard cards.Card
-		playedCards := make([]cards.Card, len(g.Players))
-		indicesToRemove := make([][]int, len(g.Players))
-
-		fmt.Printf("Starting trick %d\n", trick+1)
-		for i := 0; i < len(g.Players); i++ {
-			currentIndex := (leadIndex + i) % len(g.Players)
-			player := g.Players[currentIndex]
-
-			fmt.Printf("Player %s, your hand is: %v\n", player.Name, player.Hand)
-			fmt.Printf("Enter the index of the card you want to play: ")
-			scanner.Scan()
-			input := scanner.Text()
-			indexToPlay := ParseInput(input)
-
-			if len(indexToPlay) != 1 || indexToPlay[0] < 0 || indexToPlay[0] >= len(player.Hand) {
-				fmt.Println("Invalid card index. Try again.")
-				i-- // Ask the same player to play again
-				continue
-			}
-
-			playedCard := player.Hand[indexToPlay[0]]
-
-			if i == 0 {
-				leadCard = playedCard
-			} else {
-				if playedCard.Suit != leadCard.Suit && hasSuit(player.Hand, leadCard.Suit) {
-					fmt.Println("You must follow the suit. Try again.")
-					i-- // Ask the same player to play again
-					continue
-				}
-			}
-
-			playedCards[currentIndex] = playedCard
-			indicesToRemove[currentIndex] = []int{indexToPlay[0]}
-			fmt.Printf("Player %s played %v\n", player.Name, playedCard)
+// LegalPlays returns the indices of the cards the player may play into the
+// current trick.
+func (g *Game) LegalPlays(playerIndex int) []int {
+	if g.Stage != Trick || playerIndex < 0 || playerIndex >= len(g.Players) {
+		return nil
+	}
+	hand := g.Players[playerIndex].Hand
+	legal := []int{}
+	for i, card := range hand {
+		if g.acted == 0 || isValidTrickMove(hand, card, g.trick[g.lead]) {
+			legal = append(legal, i)
 		}
+	}
+	return legal
+}
 
-		winnerIndex := findWinner(playedCards, leadIndex)
-		fmt.Printf("Player %s wins the trick with %v\n", g.Players[winnerIndex].Name, playedCards[winnerIndex])
+// award emits a scoring event under the game's end condition and reports
+// whether it ended the game. With a roof, points other than the last trick or
+// a Chicago are cut so the score stays at or below the roof; with an exact
+// target, overshooting it bounces the score back by the excess.
+func (g *Game) award(e Event) bool {
+	player := g.Players[e.Player]
+	finishing := e.Type == EventTrickScored || e.Type == EventChicagoScored
+	if g.Rules.EndCondition == EndRoof && !finishing && e.Points > 0 {
+		if room := g.Rules.Roof - player.Score; e.Points > room {
+			e.Points = max(room, 0)
+		}
+	}
+	g.emit(e)
+	if g.Rules.EndCondition == EndExact && player.Score > g.Rules.TargetScore {
+		g.emit(Event{Type: EventBust, Player: e.Player, Points: -2 * (player.Score - g.Rules.TargetScore)})
+	}
+	return g.checkGameOver()
+}
 
-		// Remove played cards from players' hands using TossCards
-		for i := range indicesToRemove {
-			if len(indicesToRemove[i]) > 0 {
-				g.TossCards(i, indicesToRemove[i])
-			}
+func (g *Game) checkGameOver() bool {
+	if g.getHighScore() < g.Rules.TargetScore {
+		return false
+	}
+	winner := 0
+	for i, player := range g.Players {
+		if player.Score > g.Players[winner].Score {
+			winner = i
 		}
+	}
+	g.emit(Event{Type: EventGameOver, Player: winner, Points: g.Players[winner].Score})
+	return true
+}
 
-		leadIndex = winnerIndex
+// Deal gives every player a new hand, starting left of the dealer.
+func (g *Game) Deal() {
+	for i := range g.Players {
+		playerIndex := (g.dealer + 1 + i) % len(g.Players)
+		g.emit(Event{Type: EventDeal, Player: playerIndex, Cards: g.Deck.Peek(HandSize)})
 	}
-	// Award points to the player who wins the last trick
-	g.Players[leadIndex].Score += TrickWin
-	g.Round++
-	fmt.Printf("Player %s wins the trick round and gets %d points\n", g.Players[leadIndex].Name, TrickWin)
-	g.Stage = Poker
-	g.Deal()
-	fmt.Printf("Player %s wins the final poker round with a %v of %v and gets %d points\n", g.Players[bestPlayerIndex].Name, bestHandEvaluation.Rank, bestHandEvaluation.ScoreCards, bestHandEvaluation.Score)
+}
+
+func (g *Game) getHighScore() int {
+	highScore := 0
+	for _, player := range g.Players {
+		if player.Score > highScore {
+			highScore = player.Score
+		}
+	}
+	return highScore
 }
 
 // Check if a player has a card of a given suit
@@ -197,6 +485,14 @@ func hasSuit(hand []cards.Card, suit cards.Suit) bool {
 	return false
 }
 
+func isValidTrickMove(hand []cards.Card, playedCard cards.Card, leadCard cards.Card) bool {
+	// A player holding the lead suit must follow it
+	if hasSuit(hand, leadCard.Suit) {
+		return playedCard.Suit == leadCard.Suit
+	}
+	return true
+}
+
 // Find the winner of the current trick
 func findWinner(playedCards []cards.Card, leadIndex int) int {
 	leadSuit := playedCards[leadIndex].Suit
@@ -213,28 +509,37 @@ func findWinner(playedCards []cards.Card, leadIndex int) int {
 	return highestIndex
 }
 
-func (g *Game) EvaluateHands() (int, HandEvaluation) {
-	bestScore := -1
-	bestPlayerIndex := -1
-	var bestHandEvaluation HandEvaluation
-	for i := 0; i < len(g.Players)-1; i++ {
-		for j := i + 1; j < len(g.Players); j++ {
-			winningHand, winningHandEvaluation := EvaluateTwoHands(g.Players[i].Hand, g.Players[j].Hand)
-			if winningHandEvaluation.Score > bestScore {
-				bestScore = winningHandEvaluation.Score
-				bestHandEvaluation = winningHandEvaluation
-				if reflect.DeepEqual(winningHand, g.Players[i].Hand) {
-					bestPlayerIndex = i
-				} else if reflect.DeepEqual(winningHand, g.Players[j].Hand) {
-					bestPlayerIndex = j
-				}
-			}
-		}
+// EvaluateHands ranks every player's hand, best first. Players holding equal
+// hands share a place and are listed in play order, starting left of the
+// dealer. The players' hands and scores are left untouched.
+func (g *Game) EvaluateHands() []Standing {
+	return g.rankPlayers(g.playOrder())
+}
+
+// rankPlayers ranks the hands of the given players, who must be in play
+// order.
+func (g *Game) rankPlayers(players []int) []Standing {
+	hands := make([][]cards.Card, len(players))
+	for i, player := range players {
+		hands[i] = g.Players[player].Hand
 	}
 
-	if bestPlayerIndex != -1 {
-		g.Players[bestPlayerIndex].Score += bestScore
-		return bestPlayerIndex, bestHandEvaluation
+	standings := RankHands(hands)
+	for i := range standings {
+		standings[i].Player = players[standings[i].Player]
 	}
-	return 0, HandEvaluation{}
+	return standings
+}
+
+// playOrder returns every player, starting left of the dealer.
+func (g *Game) playOrder() []int {
+	order := make([]int, len(g.Players))
+	for i := range order {
+		order[i] = (g.dealer + 1 + i) % len(g.Players)
+	}
+	return order
+}
+
+func copyCards(hand []cards.Card) []cards.Card {
+	return append([]cards.Card(nil), hand...)
 }
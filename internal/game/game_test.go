@@ -1 +1,825 @@
 package game_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+func newStartedGame(t *testing.T, names ...string) *game.Game {
+	t.Helper()
+	players := []*player.Player{}
+	for _, name := range names {
+		players = append(players, player.NewPlayer(name))
+	}
+	g := game.NewGame(players)
+	if _, err := g.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return g
+}
+
+func mustApply(t *testing.T, g *game.Game, a game.Action) []game.Event {
+	t.Helper()
+	events, err := g.Apply(a)
+	if err != nil {
+		t.Fatalf("Apply(%+v) error = %v", a, err)
+	}
+	return events
+}
+
+// standPatToTricks keeps every hand through all exchanges and has everyone
+// pass on Chicago.
+func standPatToTricks(t *testing.T, g *game.Game) {
+	t.Helper()
+	for g.Stage == game.Exchange {
+		mustApply(t, g, game.StandPat(g.Turn()))
+	}
+	for g.Stage == game.Chicago {
+		mustApply(t, g, game.Pass(g.Turn()))
+	}
+	if g.Stage != game.Trick {
+		t.Fatalf("expected trick stage, got %s", g.Stage)
+	}
+}
+
+func TestStartRequiresTwoPlayers(t *testing.T) {
+	g := game.NewGame([]*player.Player{player.NewPlayer("Alice")})
+	if _, err := g.Start(); !errors.Is(err, game.ErrNotEnoughPlayers) {
+		t.Errorf("Start() error = %v, want %v", err, game.ErrNotEnoughPlayers)
+	}
+}
+
+func TestStartDealsHands(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob", "Carol")
+
+	if g.Stage != game.Exchange {
+		t.Errorf("expected stage %s, got %s", game.Exchange, g.Stage)
+	}
+	for _, p := range g.Players {
+		if len(p.Hand) != game.HandSize {
+			t.Errorf("player %s has %d cards, want %d", p.Name, len(p.Hand), game.HandSize)
+		}
+	}
+	if err := g.AddPlayer(player.NewPlayer("Dave")); !errors.Is(err, game.ErrGameStarted) {
+		t.Errorf("AddPlayer() after start error = %v, want %v", err, game.ErrGameStarted)
+	}
+}
+
+func TestApplyRejectsOutOfTurn(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+
+	if _, err := g.Apply(game.Toss(0)); !errors.Is(err, game.ErrNotYourTurn) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrNotYourTurn)
+	}
+	if _, err := g.Apply(game.Play(1, 0)); !errors.Is(err, game.ErrWrongStage) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrWrongStage)
+	}
+	if _, err := g.Apply(game.Toss(1, 1, 1)); !errors.Is(err, game.ErrInvalidCard) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrInvalidCard)
+	}
+}
+
+func TestStandPatKeepsTheHand(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	hand := fmt.Sprint(g.Players[1].Hand)
+
+	if _, err := g.Apply(game.Toss(1)); !errors.Is(err, game.ErrInvalidCard) {
+		t.Errorf("Apply(toss nothing) error = %v, want %v", err, game.ErrInvalidCard)
+	}
+	events := mustApply(t, g, game.StandPat(1))
+
+	if len(events) != 1 || events[0].Type != game.EventStandPat || events[0].Private() {
+		t.Errorf("expected a public stand pat event, got %+v", events)
+	}
+	if fmt.Sprint(g.Players[1].Hand) != hand {
+		t.Errorf("expected hand %s to be kept, got %v", hand, g.Players[1].Hand)
+	}
+	if g.Turn() != 0 {
+		t.Errorf("expected turn to pass to player 0, got %d", g.Turn())
+	}
+	mustApply(t, g, game.StandPat(0))
+	if _, err := g.Apply(game.StandPat(0)); !errors.Is(err, game.ErrNotYourTurn) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrNotYourTurn)
+	}
+}
+
+func TestTossRedrawsCards(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	kept := g.Players[1].Hand[2]
+
+	events := mustApply(t, g, game.Toss(1, 0, 1))
+
+	if len(g.Players[1].Hand) != game.HandSize {
+		t.Errorf("expected %d cards after redraw, got %d", game.HandSize, len(g.Players[1].Hand))
+	}
+	if g.Players[1].Hand[0] != kept {
+		t.Errorf("expected kept card %v first in hand, got %v", kept, g.Players[1].Hand[0])
+	}
+	if len(events) != 2 || events[0].Type != game.EventToss || events[1].Type != game.EventDraw {
+		t.Errorf("expected toss and draw events, got %+v", events)
+	}
+	if g.Turn() != 0 {
+		t.Errorf("expected turn to pass to player 0, got %d", g.Turn())
+	}
+}
+
+func TestExchangesScoreAndLeadToTricks(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	dealt := fmt.Sprint(g.Players[0].Hand, g.Players[1].Hand)
+
+	scored := 0
+	for g.Stage == game.Exchange {
+		if g.Round != scored {
+			t.Fatalf("expected exchange %d to be scored before the next one, got %d showdowns", g.Round, scored)
+		}
+		for _, e := range mustApply(t, g, game.StandPat(g.Turn())) {
+			switch e.Type {
+			case game.EventShowdown:
+				scored++
+			case game.EventNewHand, game.EventDeal:
+				t.Fatalf("expected no new deal between exchanges, got %+v", e)
+			}
+		}
+	}
+	if scored != g.Rules.Exchanges {
+		t.Errorf("expected %d scored exchanges, got %d", g.Rules.Exchanges, scored)
+	}
+	for g.Stage == game.Chicago {
+		mustApply(t, g, game.Pass(g.Turn()))
+	}
+	if kept := fmt.Sprint(g.Players[0].Hand, g.Players[1].Hand); kept != dealt {
+		t.Errorf("expected the dealt hands to be played in the tricks, got %s, dealt %s", kept, dealt)
+	}
+	if g.Stage != game.Trick || g.Turn() != 1 || g.Lead() != 1 {
+		t.Errorf("expected player 1 to lead the first trick, got stage %s turn %d", g.Stage, g.Turn())
+	}
+}
+
+func TestTrickMustFollowSuit(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	standPatToTricks(t, g)
+
+	g.Players[1].Hand = []cards.Card{{Suit: cards.Hearts, Rank: cards.Two}}
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Spades, Rank: cards.Ace},
+		{Suit: cards.Hearts, Rank: cards.Three},
+	}
+
+	mustApply(t, g, game.Play(1, 0))
+	if _, err := g.Apply(game.Play(0, 0)); !errors.Is(err, game.ErrMustFollowSuit) {
+		t.Fatalf("Apply() error = %v, want %v", err, game.ErrMustFollowSuit)
+	}
+	if legal := g.LegalPlays(0); len(legal) != 1 || legal[0] != 1 {
+		t.Errorf("LegalPlays(0) = %v, want [1]", legal)
+	}
+
+	events := mustApply(t, g, game.Play(0, 1))
+	last := events[len(events)-1]
+	if last.Type != game.EventTrickWon || last.Player != 0 {
+		t.Errorf("expected Alice to win the trick, got %+v", last)
+	}
+	if g.Turn() != 0 || g.Lead() != 0 {
+		t.Errorf("expected the trick winner to lead, got turn %d lead %d", g.Turn(), g.Lead())
+	}
+}
+
+func TestLastTrickScoresAndEndsGame(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	standPatToTricks(t, g)
+
+	for i := range g.Players {
+		g.Players[i].Score = 0
+	}
+	g.Players[1].Score = g.Rules.TargetScore - g.Rules.Scoring.LastTrick
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Ace},
+		{Suit: cards.Hearts, Rank: cards.King},
+		{Suit: cards.Hearts, Rank: cards.Queen},
+		{Suit: cards.Hearts, Rank: cards.Jack},
+		{Suit: cards.Hearts, Rank: cards.Ten},
+	}
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Spades, Rank: cards.Two},
+		{Suit: cards.Spades, Rank: cards.Three},
+		{Suit: cards.Spades, Rank: cards.Four},
+		{Suit: cards.Spades, Rank: cards.Five},
+		{Suit: cards.Spades, Rank: cards.Six},
+	}
+
+	var events []game.Event
+	for g.Stage == game.Trick {
+		events = mustApply(t, g, game.Play(g.Turn(), 0))
+	}
+
+	if g.Stage != game.Over {
+		t.Fatalf("expected game over, got stage %s", g.Stage)
+	}
+	if g.Players[1].Score != g.Rules.TargetScore {
+		t.Errorf("expected score %d, got %d", g.Rules.TargetScore, g.Players[1].Score)
+	}
+	last := events[len(events)-1]
+	if last.Type != game.EventGameOver || last.Player != 1 {
+		t.Errorf("expected Bob to win the game, got %+v", last)
+	}
+	if _, err := g.Apply(game.Play(1, 0)); !errors.Is(err, game.ErrGameOver) {
+		t.Errorf("Apply() after game over error = %v, want %v", err, game.ErrGameOver)
+	}
+}
+
+// standPatToChicago keeps every hand through all exchanges.
+func standPatToChicago(t *testing.T, g *game.Game) {
+	t.Helper()
+	for g.Stage == game.Exchange {
+		mustApply(t, g, game.StandPat(g.Turn()))
+	}
+	if g.Stage != game.Chicago {
+		t.Fatalf("expected chicago stage, got %s", g.Stage)
+	}
+}
+
+func TestDeclareOnlyBeforeFirstTrick(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob", "Carol")
+	if _, err := g.Apply(game.Declare(1)); !errors.Is(err, game.ErrWrongStage) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrWrongStage)
+	}
+	standPatToChicago(t, g)
+
+	// Declarations go round the table starting left of the dealer
+	if _, err := g.Apply(game.Declare(2)); !errors.Is(err, game.ErrNotYourTurn) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrNotYourTurn)
+	}
+	mustApply(t, g, game.Pass(1))
+	events := mustApply(t, g, game.Declare(2))
+	if g.Declarer() != 2 {
+		t.Errorf("Declarer() = %d, want 2", g.Declarer())
+	}
+	if len(events) != 2 || events[0].Type != game.EventDeclare || events[1].Stage != game.Trick {
+		t.Errorf("expected the declaration to open the tricks, got %+v", events)
+	}
+	if g.Stage != game.Trick || g.Lead() != 2 || g.Turn() != 2 {
+		t.Errorf("expected the declarer to lead the first trick, got stage %s lead %d", g.Stage, g.Lead())
+	}
+	if _, err := g.Apply(game.Declare(2)); !errors.Is(err, game.ErrWrongStage) {
+		t.Errorf("Apply() error = %v, want %v", err, game.ErrWrongStage)
+	}
+}
+
+func TestChicagoScoresInsteadOfLastTrick(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	standPatToChicago(t, g)
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Two},
+		{Suit: cards.Hearts, Rank: cards.Three},
+		{Suit: cards.Hearts, Rank: cards.Four},
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Hearts, Rank: cards.Six},
+	}
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Spades, Rank: cards.Ace},
+		{Suit: cards.Spades, Rank: cards.King},
+		{Suit: cards.Spades, Rank: cards.Queen},
+		{Suit: cards.Spades, Rank: cards.Jack},
+		{Suit: cards.Spades, Rank: cards.Nine},
+	}
+	for i := range g.Players {
+		g.Players[i].Score = 0
+	}
+
+	mustApply(t, g, game.Declare(1))
+	var scored []game.Event
+	for g.Dealer() == 0 {
+		for _, e := range mustApply(t, g, game.Play(g.Turn(), 0)) {
+			if e.Type == game.EventChicagoScored || e.Type == game.EventTrickScored {
+				scored = append(scored, e)
+			}
+		}
+	}
+
+	if len(scored) != 1 || scored[0].Type != game.EventChicagoScored || scored[0].Points != g.Rules.Scoring.Chicago {
+		t.Fatalf("expected only the chicago bonus to be scored, got %+v", scored)
+	}
+	if g.Players[1].Score != g.Rules.Scoring.Chicago || g.Players[0].Score != 0 {
+		t.Errorf("expected scores 0 and %d, got %d and %d", g.Rules.Scoring.Chicago, g.Players[0].Score, g.Players[1].Score)
+	}
+}
+
+func TestFailedChicagoCostsTheBonus(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	standPatToChicago(t, g)
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Spades, Rank: cards.Ace},
+		{Suit: cards.Hearts, Rank: cards.Three},
+		{Suit: cards.Hearts, Rank: cards.Four},
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Hearts, Rank: cards.Six},
+	}
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Spades, Rank: cards.Two},
+		{Suit: cards.Clubs, Rank: cards.King},
+		{Suit: cards.Clubs, Rank: cards.Queen},
+		{Suit: cards.Clubs, Rank: cards.Jack},
+		{Suit: cards.Clubs, Rank: cards.Nine},
+	}
+	for i := range g.Players {
+		g.Players[i].Score = 0
+	}
+
+	mustApply(t, g, game.Declare(1))
+	mustApply(t, g, game.Play(1, 0))
+	events := mustApply(t, g, game.Play(0, 0))
+
+	last := events[len(events)-1]
+	if last.Type != game.EventChicagoScored || last.Player != 1 || last.Points != -g.Rules.Scoring.Chicago {
+		t.Fatalf("expected the declarer to lose the bonus on the first lost trick, got %+v", events)
+	}
+	if g.Players[1].Score != -g.Rules.Scoring.Chicago {
+		t.Errorf("expected score %d, got %d", -g.Rules.Scoring.Chicago, g.Players[1].Score)
+	}
+
+	// The hand is played out and the last trick is scored as usual
+	lastTrick := 0
+	for g.Dealer() == 0 {
+		for _, e := range mustApply(t, g, game.Play(g.Turn(), g.LegalPlays(g.Turn())[0])) {
+			switch e.Type {
+			case game.EventChicagoScored:
+				t.Errorf("expected the failed chicago to be scored once, got %+v", e)
+			case game.EventTrickScored:
+				lastTrick++
+			}
+		}
+	}
+	if lastTrick != 1 {
+		t.Errorf("expected the last trick to be scored once, got %d", lastTrick)
+	}
+}
+
+// snapshot renders the observable state of a game for comparison.
+func snapshot(g *game.Game) string {
+	state := fmt.Sprintf("stage=%s round=%d turn=%d lead=%d trick=%v declarer=%d",
+		g.Stage, g.Round, g.Turn(), g.Lead(), g.CurrentTrick(), g.Declarer())
+	for _, p := range g.Players {
+		state += fmt.Sprintf(" %s:%d:%v", p.Name, p.Score, p.Hand)
+	}
+	if g.Deck != nil {
+		state += fmt.Sprintf(" deck=%v", g.Deck.Peek(g.Deck.NumCards))
+	}
+	return state
+}
+
+func TestReplayReconstructsEveryStep(t *testing.T) {
+	players := []*player.Player{player.NewPlayer("Alice"), player.NewPlayer("Bob"), player.NewPlayer("Carol")}
+	g := game.NewSeededGame(players, 1234)
+	if _, err := g.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	steps := map[int]string{len(g.Log()): snapshot(g)}
+	for i := 0; i < 60 && g.Stage != game.Over; i++ {
+		turn := g.Turn()
+		action := game.Toss(turn, 0, 1)
+		switch g.Stage {
+		case game.Chicago:
+			action = game.Pass(turn)
+			if turn == 2 {
+				action = game.Declare(turn)
+			}
+		case game.Trick:
+			action = game.Play(turn, g.LegalPlays(turn)[0])
+		}
+		mustApply(t, g, action)
+		steps[len(g.Log())] = snapshot(g)
+	}
+
+	record := g.Record()
+	if record.Seed != 1234 || len(record.Players) != 3 {
+		t.Fatalf("unexpected record header %d %v", record.Seed, record.Players)
+	}
+	for n, want := range steps {
+		prefix := record
+		prefix.Events = record.Events[:n]
+		replayed, err := game.Replay(prefix)
+		if err != nil {
+			t.Fatalf("Replay() of %d events error = %v", n, err)
+		}
+		if got := snapshot(replayed); got != want {
+			t.Errorf("Replay() of %d events\n got %s\nwant %s", n, got, want)
+		}
+	}
+}
+
+func TestReplayDetectsTamperedLog(t *testing.T) {
+	g := game.NewSeededGame([]*player.Player{player.NewPlayer("Alice"), player.NewPlayer("Bob")}, 99)
+	if _, err := g.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	record := g.Record()
+	record.Seed++
+
+	if _, err := game.Replay(record); !errors.Is(err, game.ErrReplayMismatch) {
+		t.Errorf("Replay() error = %v, want %v", err, game.ErrReplayMismatch)
+	}
+}
+
+func TestNewGamesHaveKeysOfTheirOwn(t *testing.T) {
+	first := newStartedGame(t, "Alice", "Bob")
+	second := newStartedGame(t, "Alice", "Bob")
+	a, b := first.Record(), second.Record()
+	if a.Key == nil || b.Key == nil {
+		t.Fatalf("a new game has no key: %+v, %+v", a.Key, b.Key)
+	}
+	if *a.Key == *b.Key {
+		t.Errorf("two new games share the key %x", *a.Key)
+	}
+	// A math/rand seed holds 31 bits; a key must use the rest as well
+	if [28]byte(a.Key[4:]) == [28]byte{} {
+		t.Errorf("the key %x fits in 31 bits", *a.Key)
+	}
+
+	// The key survives the record's JSON and rebuilds the same game
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record game.Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := game.Replay(record)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if got, want := snapshot(replayed), snapshot(first); got != want {
+		t.Errorf("Replay() of a keyed game\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestExchangesReshuffleDiscards(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob", "Carol", "Dave", "Eve", "Frank")
+
+	reshuffles := 0
+	for g.Stage == game.Exchange {
+		for _, e := range mustApply(t, g, game.Toss(g.Turn(), 0, 1, 2, 3, 4)) {
+			if e.Type == game.EventReshuffle {
+				reshuffles++
+			}
+		}
+		for _, p := range g.Players {
+			if len(p.Hand) != game.HandSize {
+				t.Fatalf("player %s holds %d cards, want %d", p.Name, len(p.Hand), game.HandSize)
+			}
+		}
+	}
+	if reshuffles == 0 {
+		t.Errorf("expected the discard pile to be reshuffled into the stock")
+	}
+}
+
+func TestRemovePlayer(t *testing.T) {
+	g := game.NewGame([]*player.Player{player.NewPlayer("Alice"), player.NewPlayer("Bob"), player.NewPlayer("Carol")})
+	if err := g.RemovePlayer(1); err != nil {
+		t.Fatalf("RemovePlayer() error = %v", err)
+	}
+	if len(g.Players) != 2 || g.Players[1].Name != "Carol" {
+		t.Fatalf("players after RemovePlayer(1) = %v, want Alice and Carol", g.Players)
+	}
+	if err := g.RemovePlayer(2); err == nil {
+		t.Errorf("expected RemovePlayer() to reject an empty seat")
+	}
+
+	if _, err := g.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := g.RemovePlayer(0); !errors.Is(err, game.ErrGameStarted) {
+		t.Errorf("RemovePlayer() after start error = %v, want %v", err, game.ErrGameStarted)
+	}
+}
+
+func TestTableSizeLimits(t *testing.T) {
+	g := game.NewGame([]*player.Player{player.NewPlayer("Alice")})
+	g.Rules.MaxPlayers = 2
+	if err := g.AddPlayer(player.NewPlayer("Bob")); err != nil {
+		t.Fatalf("AddPlayer() error = %v", err)
+	}
+	if err := g.AddPlayer(player.NewPlayer("Carol")); !errors.Is(err, game.ErrTableFull) {
+		t.Errorf("AddPlayer() error = %v, want %v", err, game.ErrTableFull)
+	}
+
+	g.Rules.MinPlayers = 3
+	if _, err := g.Start(); err == nil {
+		t.Errorf("expected Start() to reject min players above max players")
+	}
+	g.Rules.MaxPlayers = 9
+	if _, err := g.Start(); err == nil {
+		t.Errorf("expected Start() to reject tables of more than eight")
+	}
+	g.Rules.MaxPlayers = 3
+	if _, err := g.Start(); !errors.Is(err, game.ErrNotEnoughPlayers) {
+		t.Errorf("Start() error = %v, want %v", err, game.ErrNotEnoughPlayers)
+	}
+}
+
+func TestDealerRotatesEachHand(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob", "Carol")
+
+	for hand := 0; hand < 4; hand++ {
+		dealer := g.Dealer()
+		if dealer != hand%3 {
+			t.Fatalf("hand %d: expected dealer %d, got %d", hand, hand%3, dealer)
+		}
+		left := (dealer + 1) % 3
+		if g.Turn() != left {
+			t.Errorf("hand %d: expected player %d to open the exchange, got %d", hand, left, g.Turn())
+		}
+		standPatToTricks(t, g)
+		if g.Lead() != left {
+			t.Errorf("hand %d: expected player %d to lead the first trick, got %d", hand, left, g.Lead())
+		}
+		for g.Stage == game.Trick {
+			mustApply(t, g, game.Play(g.Turn(), g.LegalPlays(g.Turn())[0]))
+		}
+		for _, p := range g.Players {
+			p.Score = 0
+		}
+	}
+}
+
+func TestShowdownScoresBestOfThreeHands(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob", "Carol")
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Spades, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Nine},
+		{Suit: cards.Spades, Rank: cards.Nine},
+		{Suit: cards.Hearts, Rank: cards.Two},
+	}
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Ace},
+		{Suit: cards.Spades, Rank: cards.Ace},
+		{Suit: cards.Clubs, Rank: cards.King},
+		{Suit: cards.Spades, Rank: cards.Queen},
+		{Suit: cards.Hearts, Rank: cards.Jack},
+	}
+	g.Players[2].Hand = []cards.Card{
+		{Suit: cards.Diamonds, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Five},
+		{Suit: cards.Diamonds, Rank: cards.Nine},
+		{Suit: cards.Hearts, Rank: cards.Nine},
+		{Suit: cards.Clubs, Rank: cards.Three},
+	}
+
+	var scored []game.Event
+	for g.Round == 0 {
+		for _, e := range mustApply(t, g, game.StandPat(g.Turn())) {
+			if e.Type == game.EventHandScored {
+				scored = append(scored, e)
+			}
+		}
+	}
+
+	if len(scored) != 1 || scored[0].Player != 2 || scored[0].Rank != game.TwoPair {
+		t.Fatalf("expected Carol to score two pair, got %+v", scored)
+	}
+	if scored[0].Points != g.Rules.Scoring.TwoPair {
+		t.Errorf("expected two pair to score %d points, got %d", g.Rules.Scoring.TwoPair, scored[0].Points)
+	}
+	if g.Players[2].Score != scored[0].Points || g.Players[0].Score != 0 || g.Players[1].Score != 0 {
+		t.Errorf("expected only Carol to score, got scores %d %d %d",
+			g.Players[0].Score, g.Players[1].Score, g.Players[2].Score)
+	}
+}
+
+func TestShowdownsScoreWithTheRulesTable(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	g.Rules.Scoring = game.ClassicScoring()
+	g.Rules.Scoring.FourOfAKind = 11
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Four},
+		{Suit: cards.Spades, Rank: cards.Four},
+		{Suit: cards.Clubs, Rank: cards.Four},
+		{Suit: cards.Diamonds, Rank: cards.Four},
+		{Suit: cards.Hearts, Rank: cards.Two},
+	}
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Diamonds, Rank: cards.Queen},
+		{Suit: cards.Clubs, Rank: cards.Jack},
+		{Suit: cards.Diamonds, Rank: cards.Eight},
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Three},
+	}
+
+	var scored []game.Event
+	for g.Round == 0 {
+		for _, e := range mustApply(t, g, game.StandPat(g.Turn())) {
+			if e.Type == game.EventHandScored {
+				scored = append(scored, e)
+			}
+		}
+	}
+	if len(scored) != 1 || scored[0].Player != 0 || scored[0].Points != 11 {
+		t.Errorf("expected Alice to score 11 points for four of a kind, got %+v", scored)
+	}
+}
+
+func TestHighCardShowdownScoresAndShowsNothing(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Ace},
+		{Suit: cards.Spades, Rank: cards.King},
+		{Suit: cards.Clubs, Rank: cards.Nine},
+		{Suit: cards.Spades, Rank: cards.Seven},
+		{Suit: cards.Hearts, Rank: cards.Two},
+	}
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Diamonds, Rank: cards.Queen},
+		{Suit: cards.Clubs, Rank: cards.Jack},
+		{Suit: cards.Diamonds, Rank: cards.Eight},
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Three},
+	}
+
+	for g.Round == 0 {
+		for _, e := range mustApply(t, g, game.StandPat(g.Turn())) {
+			if e.Type == game.EventHandScored {
+				t.Errorf("a high card showdown scored %+v: %s", e, g.Describe(e))
+			}
+		}
+	}
+	if g.Players[0].Score != 0 || g.Players[1].Score != 0 {
+		t.Errorf("expected no scores, got %d %d", g.Players[0].Score, g.Players[1].Score)
+	}
+}
+
+func TestTrickBonusesScoreAtTheEndOfTheHand(t *testing.T) {
+	g := newStartedGame(t, "Alice", "Bob")
+	g.Rules.TrickBonuses = []game.TrickBonus{
+		{Hook: "last_trick_deuce", Points: 5},
+		{Hook: "last_trick_ace_lead", Points: -5},
+	}
+	standPatToTricks(t, g)
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Three},
+		{Suit: cards.Hearts, Rank: cards.Four},
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Hearts, Rank: cards.Six},
+		{Suit: cards.Hearts, Rank: cards.Seven},
+	}
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Clubs, Rank: cards.Ace},
+		{Suit: cards.Clubs, Rank: cards.King},
+		{Suit: cards.Clubs, Rank: cards.Queen},
+		{Suit: cards.Clubs, Rank: cards.Jack},
+		{Suit: cards.Clubs, Rank: cards.Two},
+	}
+	for _, p := range g.Players {
+		p.Score = 0
+	}
+
+	var bonuses []game.Event
+	for g.Dealer() == 0 {
+		for _, e := range mustApply(t, g, game.Play(g.Turn(), 0)) {
+			if e.Type == game.EventBonusScored {
+				bonuses = append(bonuses, e)
+			}
+		}
+	}
+
+	if len(bonuses) != 1 || bonuses[0].Player != 1 || bonuses[0].Points != 5 || bonuses[0].Hook != "last_trick_deuce" {
+		t.Fatalf("expected Bob to score the deuce bonus only, got %+v", bonuses)
+	}
+	if want := g.Rules.Scoring.LastTrick + 5; g.Players[1].Score != want {
+		t.Errorf("expected Bob to score %d, got %d", want, g.Players[1].Score)
+	}
+}
+
+// newShowdownGame starts a three player game with player 0 dealing, in which
+// Carol holds two pair and Alice holds the same two pair with a lower kicker
+// unless tied is set.
+func newShowdownGame(t *testing.T, rules game.Rules, tied bool) *game.Game {
+	t.Helper()
+	g := game.NewGame([]*player.Player{player.NewPlayer("Alice"), player.NewPlayer("Bob"), player.NewPlayer("Carol")})
+	g.Rules = rules
+	if _, err := g.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	g.Players[0].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Spades, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Nine},
+		{Suit: cards.Spades, Rank: cards.Nine},
+		{Suit: cards.Hearts, Rank: cards.Two},
+	}
+	g.Players[1].Hand = []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Ace},
+		{Suit: cards.Spades, Rank: cards.Ace},
+		{Suit: cards.Clubs, Rank: cards.King},
+		{Suit: cards.Spades, Rank: cards.Queen},
+		{Suit: cards.Hearts, Rank: cards.Jack},
+	}
+	kicker := cards.Card{Suit: cards.Clubs, Rank: cards.Three}
+	if tied {
+		kicker.Rank = cards.Two
+	}
+	g.Players[2].Hand = []cards.Card{
+		{Suit: cards.Diamonds, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Five},
+		{Suit: cards.Diamonds, Rank: cards.Nine},
+		{Suit: cards.Hearts, Rank: cards.Nine},
+		kicker,
+	}
+	for _, p := range g.Players {
+		p.Score = 0
+	}
+	return g
+}
+
+// standPatOneExchange has everyone keep their hand for one exchange and
+// returns the events of the showdown.
+func standPatOneExchange(t *testing.T, g *game.Game) []game.Event {
+	t.Helper()
+	var events []game.Event
+	for round := g.Round; g.Round == round && g.Stage == game.Exchange; {
+		events = append(events, mustApply(t, g, game.StandPat(g.Turn()))...)
+	}
+	return events
+}
+
+func TestExactTargetBustsBack(t *testing.T) {
+	rules := game.DefaultRules()
+	rules.EndCondition = game.EndExact
+	g := newShowdownGame(t, rules, false)
+	g.Players[2].Score = rules.TargetScore - 1
+
+	events := standPatOneExchange(t, g)
+
+	last := events[len(events)-1]
+	if last.Type != game.EventBust || last.Player != 2 || last.Points != -2*(rules.Scoring.TwoPair-1) {
+		t.Fatalf("expected Carol to bust, got %+v", events)
+	}
+	if want := rules.TargetScore + 1 - rules.Scoring.TwoPair; g.Players[2].Score != want {
+		t.Errorf("expected Carol to fall back to %d, got %d", want, g.Players[2].Score)
+	}
+	if g.Stage == game.Over {
+		t.Error("expected an overshoot not to end the game")
+	}
+
+	g.Players[2].Score = rules.TargetScore - rules.Scoring.TwoPair
+	standPatOneExchange(t, g)
+	if g.Stage != game.Over || g.Players[2].Score != rules.TargetScore {
+		t.Errorf("expected hitting %d exactly to end the game, got stage %s score %d", rules.TargetScore, g.Stage, g.Players[2].Score)
+	}
+}
+
+func TestRoofCapsHandScoring(t *testing.T) {
+	rules := game.DefaultRules()
+	rules.EndCondition = game.EndRoof
+	rules.Roof = 46
+	g := newShowdownGame(t, rules, false)
+	g.Players[2].Score = rules.Roof - 1
+
+	for g.Stage == game.Exchange {
+		for _, e := range standPatOneExchange(t, g) {
+			if e.Type == game.EventHandScored && g.Players[2].Score > rules.Roof {
+				t.Fatalf("expected hand scoring to stop at %d, got %+v", rules.Roof, e)
+			}
+		}
+	}
+	if g.Players[2].Score != rules.Roof || g.Stage != game.Chicago {
+		t.Fatalf("expected Carol to sit at the roof in the Chicago stage, got %d in %s", g.Players[2].Score, g.Stage)
+	}
+
+	// Only the last trick can take a player past the roof
+	for g.Stage == game.Chicago {
+		mustApply(t, g, game.Pass(g.Turn()))
+	}
+	for g.Stage == game.Trick {
+		mustApply(t, g, game.Play(g.Turn(), g.LegalPlays(g.Turn())[0]))
+	}
+	if g.Stage == game.Over && g.Players[2].Score < rules.TargetScore {
+		t.Errorf("expected the game to end only on the target, got scores %d %d %d",
+			g.Players[0].Score, g.Players[1].Score, g.Players[2].Score)
+	}
+}
+
+func TestScoringEndsTheGameImmediately(t *testing.T) {
+	rules := game.DefaultRules()
+	rules.TiePolicy = game.TieSplit
+	g := newShowdownGame(t, rules, true)
+	g.Players[0].Score = rules.TargetScore - 1
+	g.Players[2].Score = rules.TargetScore - 1
+
+	events := standPatOneExchange(t, g)
+
+	last := events[len(events)-1]
+	if g.Stage != game.Over || last.Type != game.EventGameOver || last.Player != 2 {
+		t.Fatalf("expected Carol, first in play order, to win at once, got %+v", events)
+	}
+	if g.Players[0].Score != rules.TargetScore-1 {
+		t.Errorf("expected Alice not to score after the game ended, got %d", g.Players[0].Score)
+	}
+}
@@ -16,3 +16,12 @@ func ParseInput(input string) []int {
 	}
 	return indices
 }
+
+// ParseYes reports whether a typed answer means yes.
+func ParseYes(input string) bool {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -1,8 +1,9 @@
 package game
 
 import (
-	"reflect"
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/antongollbo123/chicago-poker/pkg/cards"
 )
@@ -19,6 +20,7 @@ const (
 	FullHouse
 	FourOfAKind
 	StraightFlush
+	RoyalStraightFlush
 )
 
 func (hr HandRank) String() string {
@@ -41,16 +43,81 @@ func (hr HandRank) String() string {
 		return "Four of a Kind"
 	case StraightFlush:
 		return "Straight Flush"
+	case RoyalStraightFlush:
+		return "Royal Straight Flush"
 	default:
 		return "Unknown Hand Rank"
 	}
 }
 
+// ParseHandRank reads a hand category by its name, such as "two pair" or
+// "Full House", ignoring case and surrounding spaces.
+func ParseHandRank(name string) (HandRank, bool) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	for rank := HighCard; rank <= RoyalStraightFlush; rank++ {
+		if strings.ToLower(rank.String()) == name {
+			return rank, true
+		}
+	}
+	return HighCard, false
+}
+
+// MarshalText writes a hand rank by its name, so logs and clients read
+// "Two Pair" rather than a number.
+func (hr HandRank) MarshalText() ([]byte, error) {
+	return []byte(hr.String()), nil
+}
+
+func (hr *HandRank) UnmarshalText(text []byte) error {
+	rank, ok := ParseHandRank(string(text))
+	if !ok {
+		return fmt.Errorf("unknown hand rank %q", text)
+	}
+	*hr = rank
+	return nil
+}
+
 type HandEvaluation struct { // TODO: Rename to Hand ?
 	Rank       HandRank
-	Score      int
-	ScoreCards []cards.Card
-	// TODO: Add high card?
+	ScoreCards []cards.Card // cards making up the hand rank, biggest group first
+	Kickers    []cards.Card // remaining cards, highest first
+}
+
+// newEvaluation orders the scoring cards and kickers of a hand so that two
+// evaluations of the same rank can be compared position by position: larger
+// groups first (the triple of a full house before its pair), then higher
+// ranks, then higher suits. Straights keep the order isStraight gives them,
+// which puts the Ace of a wheel last.
+func newEvaluation(hand []cards.Card, rank HandRank, scoreCards []cards.Card) HandEvaluation {
+	scoreCards = copyCards(scoreCards)
+	groupSize := make(map[cards.Rank]int)
+	for _, card := range scoreCards {
+		groupSize[card.Rank]++
+	}
+	switch rank {
+	case Straight, StraightFlush, RoyalStraightFlush:
+	default:
+		sort.SliceStable(scoreCards, func(i, j int) bool {
+			if groupSize[scoreCards[i].Rank] != groupSize[scoreCards[j].Rank] {
+				return groupSize[scoreCards[i].Rank] > groupSize[scoreCards[j].Rank]
+			}
+			return higherCard(scoreCards[i], scoreCards[j])
+		})
+	}
+
+	kickers, _ := removeCards(hand, scoreCards)
+	sort.SliceStable(kickers, func(i, j int) bool {
+		return higherCard(kickers[i], kickers[j])
+	})
+	return HandEvaluation{Rank: rank, ScoreCards: scoreCards, Kickers: kickers}
+}
+
+// higherCard orders cards by rank, then by suit.
+func higherCard(card1, card2 cards.Card) bool {
+	if card1.Rank != card2.Rank {
+		return card1.Rank > card2.Rank
+	}
+	return cards.SuitValue(string(card1.Suit)) > cards.SuitValue(string(card2.Suit))
 }
 
 func EvaluateHand(hand []cards.Card) HandEvaluation {
@@ -65,53 +132,57 @@ func EvaluateHand(hand []cards.Card) HandEvaluation {
 	isFlush := len(suitCounts) == 1
 	straightCards, isStraight := isStraight(hand)
 	switch {
+	case isStraight && isFlush && straightCards[0].Rank == cards.Ace:
+		return newEvaluation(hand, RoyalStraightFlush, straightCards)
 	case isStraight && isFlush:
-		return HandEvaluation{Rank: StraightFlush, Score: 8, ScoreCards: straightCards}
+		return newEvaluation(hand, StraightFlush, straightCards)
 	default:
 		if nOfAKindCards, ok := getNOfAKind(rankCounts, 4); ok {
-			return HandEvaluation{Rank: FourOfAKind, Score: 7, ScoreCards: nOfAKindCards}
+			return newEvaluation(hand, FourOfAKind, nOfAKindCards)
 		}
 		if fullHouseCards, ok := getFullHouse(rankCounts); ok {
-			return HandEvaluation{Rank: FullHouse, Score: 6, ScoreCards: fullHouseCards}
+			return newEvaluation(hand, FullHouse, fullHouseCards)
 		}
 		if isFlush {
-			return HandEvaluation{Rank: Flush, Score: 5, ScoreCards: hand}
+			return newEvaluation(hand, Flush, hand)
 		}
 		if isStraight {
-			return HandEvaluation{Rank: Straight, Score: 4, ScoreCards: straightCards}
+			return newEvaluation(hand, Straight, straightCards)
 		}
 		if nOfAKindCards, ok := getNOfAKind(rankCounts, 3); ok {
-			return HandEvaluation{Rank: Triple, Score: 3, ScoreCards: nOfAKindCards}
+			return newEvaluation(hand, Triple, nOfAKindCards)
 		}
 		if twoPairCards, ok := getTwoPair(rankCounts); ok {
-			return HandEvaluation{Rank: TwoPair, Score: 2, ScoreCards: twoPairCards}
+			return newEvaluation(hand, TwoPair, twoPairCards)
 		}
 		if nOfAKindCards, ok := getNOfAKind(rankCounts, 2); ok {
-			return HandEvaluation{Rank: Pair, Score: 1, ScoreCards: nOfAKindCards}
+			return newEvaluation(hand, Pair, nOfAKindCards)
 		}
 	}
-	return HandEvaluation{Rank: HighCard, Score: 0, ScoreCards: hand}
+	return newEvaluation(hand, HighCard, hand)
 }
 
+// isStraight reports whether the hand is five consecutive ranks and returns
+// its cards highest first. The Ace plays high, or low in A-2-3-4-5 (the
+// wheel), where it is returned last as the lowest card.
 func isStraight(hand []cards.Card) ([]cards.Card, bool) {
 	if len(hand) < 5 {
 		return nil, false
 	}
-	ranks := []int{}
-	rankMap := map[cards.Rank]int{
-		cards.Two: 2, cards.Three: 3, cards.Four: 4, cards.Five: 5, cards.Six: 6, cards.Seven: 7,
-		cards.Eight: 8, cards.Nine: 9, cards.Ten: 10, cards.Jack: 11, cards.Queen: 12, cards.King: 13, cards.Ace: 14,
+	straightCards := sortCards(copyCards(hand))
+	if straightCards[0].Rank == cards.Ace && straightCards[1].Rank == cards.Five {
+		straightCards = append(straightCards[1:], straightCards[0])
 	}
-	for _, card := range hand {
-		ranks = append(ranks, rankMap[card.Rank])
-	}
-	sort.Ints(ranks)
-	for i := 0; i < len(ranks)-1; i++ {
-		if ranks[i+1] != ranks[i]+1 {
+	for i := 0; i < len(straightCards)-1; i++ {
+		high, low := straightCards[i].Rank, straightCards[i+1].Rank
+		if low == cards.Ace {
+			low = 1 // the Ace of a wheel
+		}
+		if high != low+1 {
 			return nil, false
 		}
 	}
-	return hand, true
+	return straightCards, true
 }
 
 func getNOfAKind(rankCounts map[cards.Rank][]cards.Card, n int) ([]cards.Card, bool) {
@@ -155,53 +226,60 @@ func getFullHouse(rankCounts map[cards.Rank][]cards.Card) ([]cards.Card, bool) {
 	return nil, false
 }
 
+// EvaluateTwoHands returns the better of two hands, sorted highest card
+// first, with its evaluation. Hands of equal rank are compared by their
+// primary group, then any secondary group, then the kickers; exact ties are
+// broken by suit. Nil is returned only for two identical hands.
 func EvaluateTwoHands(hand1, hand2 []cards.Card) ([]cards.Card, HandEvaluation) {
 	hand1Eval := EvaluateHand(hand1)
 	hand2Eval := EvaluateHand(hand2)
 
-	// COMPARE RANKS; i.e. PAIR with PAIR, PAIR with TRIPLE etc.
-	if hand1Eval.Rank != hand2Eval.Rank {
-		if hand1Eval.Rank > hand2Eval.Rank {
-			return hand1, hand1Eval
-		} else if hand2Eval.Rank > hand1Eval.Rank {
-			return hand2, hand2Eval
-		}
+	result := CompareEvaluations(hand1Eval, hand2Eval)
+	if result == 0 {
+		result = compareSuits(hand1Eval, hand2Eval)
 	}
-	// Sort suit must be done before sort cards!
-	hand1Eval.ScoreCards = sortCards(hand1Eval.ScoreCards)
-	hand2Eval.ScoreCards = sortCards(hand2Eval.ScoreCards)
-
-	hand1 = sortCards(hand1)
-	hand2 = sortCards(hand2)
+	switch {
+	case result > 0:
+		return sortCards(copyCards(hand1)), hand1Eval
+	case result < 0:
+		return sortCards(copyCards(hand2)), hand2Eval
+	}
+	return nil, HandEvaluation{}
+}
 
-	for i := 0; i < len(hand1Eval.ScoreCards); i++ {
-		if hand1Eval.ScoreCards[i].Rank > hand2Eval.ScoreCards[i].Rank {
-			return hand1, hand1Eval
-		} else if hand1Eval.ScoreCards[i].Rank < hand2Eval.ScoreCards[i].Rank {
-			return hand2, hand2Eval
-		}
+// CompareEvaluations returns a positive number if the first hand wins, a
+// negative number if the second does and 0 if they are equal in rank. Ranks
+// are compared first, then the scoring cards and kickers position by
+// position. Suits never matter.
+func CompareEvaluations(hand1Eval, hand2Eval HandEvaluation) int {
+	if hand1Eval.Rank != hand2Eval.Rank {
+		return int(hand1Eval.Rank) - int(hand2Eval.Rank)
 	}
-	for i := 0; i < len(hand1); i++ {
-		if hand1[i].Rank > hand2[i].Rank {
-			return hand1, hand1Eval
-		} else if hand1[i].Rank < hand2[i].Rank {
-			return hand2, hand2Eval
-		}
+	if c := compareRanks(hand1Eval.ScoreCards, hand2Eval.ScoreCards); c != 0 {
+		return c
 	}
-	winningHand := compareSuit(hand1Eval.ScoreCards, hand2Eval.ScoreCards)
+	return compareRanks(hand1Eval.Kickers, hand2Eval.Kickers)
+}
 
-	if reflect.DeepEqual(winningHand, hand1Eval.ScoreCards) {
-		return hand1, hand1Eval
-	} else if reflect.DeepEqual(winningHand, hand2Eval.ScoreCards) {
-		return hand2, hand2Eval
+// compareSuits breaks an exact tie by comparing suits in the order the cards
+// are ranked, using cards.SuitValue. Two different hands never tie on suit.
+func compareSuits(hand1Eval, hand2Eval HandEvaluation) int {
+	cards1 := append(copyCards(hand1Eval.ScoreCards), hand1Eval.Kickers...)
+	cards2 := append(copyCards(hand2Eval.ScoreCards), hand2Eval.Kickers...)
+	for i := 0; i < len(cards1) && i < len(cards2); i++ {
+		suit1 := cards.SuitValue(string(cards1[i].Suit))
+		suit2 := cards.SuitValue(string(cards2[i].Suit))
+		if suit1 != suit2 {
+			return suit1 - suit2
+		}
 	}
-	return nil, HandEvaluation{}
+	return 0
 }
 
+// sortCards sorts cards in place, highest rank first and by suit within a rank.
 func sortCards(cards []cards.Card) []cards.Card {
-	cards = sortSuit(cards)
-	sort.Slice(cards, func(i, j int) bool {
-		return cards[i].Rank > cards[j].Rank
+	sort.SliceStable(cards, func(i, j int) bool {
+		return higherCard(cards[i], cards[j])
 	})
 	return cards
 }
@@ -214,19 +292,6 @@ func sortSuit(cards_ []cards.Card) []cards.Card {
 
 }
 
-func compareSuit(hand1, hand2 []cards.Card) []cards.Card {
-	for i := 0; i < len(hand1); i++ {
-		if cards.SuitValue(string(hand1[i].Suit)) > cards.SuitValue(string(hand2[i].Suit)) {
-			return hand1
-		} else if cards.SuitValue(string(hand1[i].Suit)) < cards.SuitValue(string(hand2[i].Suit)) {
-			return hand2
-		} else {
-			continue
-		}
-	}
-	return nil
-}
-
 type ConditionFunc func(cards.Card) bool
 
 func filterCards(cardList []cards.Card, condition ConditionFunc) []cards.Card {
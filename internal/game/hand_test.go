@@ -22,7 +22,7 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Hearts, Rank: cards.Seven},
 				{Suit: cards.Hearts, Rank: cards.Nine},
 			},
-			expected: HandEvaluation{Rank: HighCard, Score: 0},
+			expected: HandEvaluation{Rank: HighCard},
 		},
 		{
 			name: "Pair",
@@ -33,7 +33,7 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Hearts, Rank: cards.Eight},
 				{Suit: cards.Hearts, Rank: cards.Four},
 			},
-			expected: HandEvaluation{Rank: Pair, Score: 1},
+			expected: HandEvaluation{Rank: Pair},
 		},
 		{
 			name: "TwoPair",
@@ -44,7 +44,7 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Hearts, Rank: cards.Three},
 				{Suit: cards.Hearts, Rank: cards.Four},
 			},
-			expected: HandEvaluation{Rank: TwoPair, Score: 2},
+			expected: HandEvaluation{Rank: TwoPair},
 		},
 		{
 			name: "Triple",
@@ -55,7 +55,7 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Hearts, Rank: cards.Eight},
 				{Suit: cards.Hearts, Rank: cards.Four},
 			},
-			expected: HandEvaluation{Rank: Triple, Score: 3},
+			expected: HandEvaluation{Rank: Triple},
 		},
 		{
 			name: "Straight",
@@ -66,7 +66,7 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Spades, Rank: cards.Eight},
 				{Suit: cards.Hearts, Rank: cards.Nine},
 			},
-			expected: HandEvaluation{Rank: Straight, Score: 4},
+			expected: HandEvaluation{Rank: Straight},
 		},
 		{
 			name: "Flush",
@@ -77,7 +77,7 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Hearts, Rank: cards.Eight},
 				{Suit: cards.Hearts, Rank: cards.Ten},
 			},
-			expected: HandEvaluation{Rank: Flush, Score: 5},
+			expected: HandEvaluation{Rank: Flush},
 		},
 		{
 			name: "FullHouse",
@@ -88,7 +88,7 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Hearts, Rank: cards.Four},
 				{Suit: cards.Spades, Rank: cards.Four},
 			},
-			expected: HandEvaluation{Rank: FullHouse, Score: 6},
+			expected: HandEvaluation{Rank: FullHouse},
 		},
 		{
 			name: "FourOfAKind",
@@ -99,7 +99,7 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Clubs, Rank: cards.Four},
 				{Suit: cards.Hearts, Rank: cards.Two},
 			},
-			expected: HandEvaluation{Rank: FourOfAKind, Score: 7},
+			expected: HandEvaluation{Rank: FourOfAKind},
 		},
 		{
 			name: "StraightFlush",
@@ -110,14 +110,47 @@ func TestEvaluateHand(t *testing.T) {
 				{Suit: cards.Hearts, Rank: cards.Eight},
 				{Suit: cards.Hearts, Rank: cards.Nine},
 			},
-			expected: HandEvaluation{Rank: StraightFlush, Score: 8},
+			expected: HandEvaluation{Rank: StraightFlush},
+		},
+		{
+			name: "WheelStraight",
+			hand: []cards.Card{
+				{Suit: cards.Hearts, Rank: cards.Ace},
+				{Suit: cards.Spades, Rank: cards.Two},
+				{Suit: cards.Hearts, Rank: cards.Three},
+				{Suit: cards.Spades, Rank: cards.Four},
+				{Suit: cards.Hearts, Rank: cards.Five},
+			},
+			expected: HandEvaluation{Rank: Straight},
+		},
+		{
+			name: "WheelStraightFlush",
+			hand: []cards.Card{
+				{Suit: cards.Clubs, Rank: cards.Five},
+				{Suit: cards.Clubs, Rank: cards.Ace},
+				{Suit: cards.Clubs, Rank: cards.Three},
+				{Suit: cards.Clubs, Rank: cards.Two},
+				{Suit: cards.Clubs, Rank: cards.Four},
+			},
+			expected: HandEvaluation{Rank: StraightFlush},
+		},
+		{
+			name: "RoyalStraightFlush",
+			hand: []cards.Card{
+				{Suit: cards.Spades, Rank: cards.Queen},
+				{Suit: cards.Spades, Rank: cards.Ace},
+				{Suit: cards.Spades, Rank: cards.Ten},
+				{Suit: cards.Spades, Rank: cards.King},
+				{Suit: cards.Spades, Rank: cards.Jack},
+			},
+			expected: HandEvaluation{Rank: RoyalStraightFlush},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := EvaluateHand(tt.hand)
-			if result.Rank != tt.expected.Rank && result.Score != tt.expected.Score {
+			if result.Rank != tt.expected.Rank {
 				t.Errorf("EvaluateHand(%v) = %v, want %v", tt.hand, result, tt.expected)
 			}
 		})
@@ -149,6 +182,26 @@ func TestIsStraight(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			hand: []cards.Card{
+				{Suit: cards.Hearts, Rank: cards.Ace},
+				{Suit: cards.Spades, Rank: cards.Two},
+				{Suit: cards.Hearts, Rank: cards.Three},
+				{Suit: cards.Spades, Rank: cards.Four},
+				{Suit: cards.Hearts, Rank: cards.Five},
+			},
+			expected: true,
+		},
+		{
+			hand: []cards.Card{
+				{Suit: cards.Hearts, Rank: cards.Queen},
+				{Suit: cards.Spades, Rank: cards.King},
+				{Suit: cards.Hearts, Rank: cards.Ace},
+				{Suit: cards.Spades, Rank: cards.Two},
+				{Suit: cards.Hearts, Rank: cards.Three},
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -438,3 +491,199 @@ func TestEvaluteTwoHands(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareEvaluationsGroupsAndKickers(t *testing.T) {
+	tests := []struct {
+		name  string
+		hand1 []cards.Card
+		hand2 []cards.Card
+		want  int
+	}{
+		{
+			name: "FullHouseComparesTripleBeforePair",
+			hand1: []cards.Card{
+				{Suit: cards.Hearts, Rank: cards.Five},
+				{Suit: cards.Spades, Rank: cards.Five},
+				{Suit: cards.Clubs, Rank: cards.Five},
+				{Suit: cards.Hearts, Rank: cards.Two},
+				{Suit: cards.Spades, Rank: cards.Two},
+			},
+			hand2: []cards.Card{
+				{Suit: cards.Hearts, Rank: cards.Four},
+				{Suit: cards.Spades, Rank: cards.Four},
+				{Suit: cards.Clubs, Rank: cards.Four},
+				{Suit: cards.Hearts, Rank: cards.Ace},
+				{Suit: cards.Spades, Rank: cards.Ace},
+			},
+			want: 1,
+		},
+		{
+			name: "TwoPairComparesLowPairBeforeKicker",
+			hand1: []cards.Card{
+				{Suit: cards.Hearts, Rank: cards.King},
+				{Suit: cards.Spades, Rank: cards.King},
+				{Suit: cards.Clubs, Rank: cards.Three},
+				{Suit: cards.Hearts, Rank: cards.Three},
+				{Suit: cards.Spades, Rank: cards.Ace},
+			},
+			hand2: []cards.Card{
+				{Suit: cards.Diamonds, Rank: cards.King},
+				{Suit: cards.Clubs, Rank: cards.King},
+				{Suit: cards.Diamonds, Rank: cards.Four},
+				{Suit: cards.Spades, Rank: cards.Four},
+				{Suit: cards.Hearts, Rank: cards.Two},
+			},
+			want: -1,
+		},
+		{
+			name: "TwoPairFallsBackToKicker",
+			hand1: []cards.Card{
+				{Suit: cards.Hearts, Rank: cards.King},
+				{Suit: cards.Spades, Rank: cards.King},
+				{Suit: cards.Clubs, Rank: cards.Three},
+				{Suit: cards.Hearts, Rank: cards.Three},
+				{Suit: cards.Spades, Rank: cards.Seven},
+			},
+			hand2: []cards.Card{
+				{Suit: cards.Diamonds, Rank: cards.King},
+				{Suit: cards.Clubs, Rank: cards.King},
+				{Suit: cards.Diamonds, Rank: cards.Three},
+				{Suit: cards.Spades, Rank: cards.Three},
+				{Suit: cards.Hearts, Rank: cards.Six},
+			},
+			want: 1,
+		},
+		{
+			name: "ExactTieIgnoresSuits",
+			hand1: []cards.Card{
+				{Suit: cards.Hearts, Rank: cards.Nine},
+				{Suit: cards.Spades, Rank: cards.Nine},
+				{Suit: cards.Clubs, Rank: cards.Four},
+				{Suit: cards.Hearts, Rank: cards.Three},
+				{Suit: cards.Spades, Rank: cards.Two},
+			},
+			hand2: []cards.Card{
+				{Suit: cards.Diamonds, Rank: cards.Nine},
+				{Suit: cards.Clubs, Rank: cards.Nine},
+				{Suit: cards.Diamonds, Rank: cards.Four},
+				{Suit: cards.Spades, Rank: cards.Three},
+				{Suit: cards.Hearts, Rank: cards.Two},
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareEvaluations(EvaluateHand(tt.hand1), EvaluateHand(tt.hand2))
+			if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+				t.Errorf("CompareEvaluations() = %d, want sign of %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTie(t *testing.T) {
+	hearts := []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Nine},
+		{Suit: cards.Clubs, Rank: cards.Nine},
+		{Suit: cards.Clubs, Rank: cards.Four},
+		{Suit: cards.Hearts, Rank: cards.Three},
+		{Suit: cards.Spades, Rank: cards.Two},
+	}
+	spades := []cards.Card{
+		{Suit: cards.Diamonds, Rank: cards.Nine},
+		{Suit: cards.Spades, Rank: cards.Nine},
+		{Suit: cards.Diamonds, Rank: cards.Four},
+		{Suit: cards.Spades, Rank: cards.Three},
+		{Suit: cards.Hearts, Rank: cards.Two},
+	}
+	winners := Winners(RankHands([][]cards.Card{spades, hearts}))
+	if len(winners) != 2 {
+		t.Fatalf("expected a two-way tie, got %d winners", len(winners))
+	}
+
+	tests := []struct {
+		policy  TiePolicy
+		players []int
+	}{
+		{policy: TieSplit, players: []int{0, 1}},
+		{policy: TieSuit, players: []int{1}},
+		{policy: TieFirst, players: []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			got := ResolveTie(tt.policy, winners)
+			if len(got) != len(tt.players) {
+				t.Fatalf("ResolveTie() returned %d players, want %v", len(got), tt.players)
+			}
+			for i, s := range got {
+				if s.Player != tt.players[i] {
+					t.Errorf("ResolveTie() player %d = %d, want %d", i, s.Player, tt.players[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWheelIsTheLowestStraight(t *testing.T) {
+	wheel := []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Ace},
+		{Suit: cards.Spades, Rank: cards.Two},
+		{Suit: cards.Hearts, Rank: cards.Three},
+		{Suit: cards.Spades, Rank: cards.Four},
+		{Suit: cards.Hearts, Rank: cards.Five},
+	}
+	sixHigh := []cards.Card{
+		{Suit: cards.Clubs, Rank: cards.Two},
+		{Suit: cards.Diamonds, Rank: cards.Three},
+		{Suit: cards.Clubs, Rank: cards.Four},
+		{Suit: cards.Diamonds, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Six},
+	}
+	wheelFlush := []cards.Card{
+		{Suit: cards.Diamonds, Rank: cards.Ace},
+		{Suit: cards.Diamonds, Rank: cards.Two},
+		{Suit: cards.Diamonds, Rank: cards.Three},
+		{Suit: cards.Diamonds, Rank: cards.Four},
+		{Suit: cards.Diamonds, Rank: cards.Five},
+	}
+	royal := []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Ten},
+		{Suit: cards.Hearts, Rank: cards.Jack},
+		{Suit: cards.Hearts, Rank: cards.Queen},
+		{Suit: cards.Hearts, Rank: cards.King},
+		{Suit: cards.Hearts, Rank: cards.Ace},
+	}
+
+	wheelEval := EvaluateHand(wheel)
+	if got := wheelEval.ScoreCards[len(wheelEval.ScoreCards)-1].Rank; got != cards.Ace {
+		t.Errorf("expected the Ace of a wheel to rank last, got %v", wheelEval.ScoreCards)
+	}
+	if CompareEvaluations(wheelEval, EvaluateHand(sixHigh)) >= 0 {
+		t.Errorf("expected a six-high straight to beat the wheel")
+	}
+	if CompareEvaluations(EvaluateHand(royal), EvaluateHand(wheelFlush)) <= 0 {
+		t.Errorf("expected a royal straight flush to beat a straight flush")
+	}
+	standings := RankHands([][]cards.Card{wheelFlush, royal, sixHigh, wheel})
+	for i, player := range []int{1, 0, 2, 3} {
+		if standings[i].Player != player {
+			t.Errorf("standing %d = player %d, want %d", i, standings[i].Player, player)
+		}
+	}
+}
+
+func TestParseHandRank(t *testing.T) {
+	for rank := HighCard; rank <= RoyalStraightFlush; rank++ {
+		if got, ok := ParseHandRank(rank.String()); !ok || got != rank {
+			t.Errorf("ParseHandRank(%q) = %v, %v", rank.String(), got, ok)
+		}
+	}
+	if got, ok := ParseHandRank("  two   PAIR "); !ok || got != TwoPair {
+		t.Errorf("ParseHandRank() = %v, %v, want %v", got, ok, TwoPair)
+	}
+	if _, ok := ParseHandRank("nothing"); ok {
+		t.Error("expected an unknown hand to be rejected")
+	}
+}
@@ -0,0 +1,205 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/antongollbo123/chicago-poker/internal/deck"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+// Record is everything needed to rebuild a game: its seed or key, the
+// players in seating order and the event log.
+type Record struct {
+	Seed    int64     `json:"seed"`
+	Key     *deck.Key `json:"key,omitempty"`
+	Rules   Rules     `json:"rules"`
+	Players []string  `json:"players"`
+	Events  []Event   `json:"events"`
+}
+
+// Log returns a copy of every event recorded since the game started.
+func (g *Game) Log() []Event {
+	return append([]Event(nil), g.log...)
+}
+
+// Record returns the seed, rules, players and log of the game.
+func (g *Game) Record() Record {
+	names := make([]string, len(g.Players))
+	for i, player := range g.Players {
+		names[i] = player.Name
+	}
+	return Record{Seed: g.seed, Key: g.key, Rules: g.Rules, Players: names, Events: g.Log()}
+}
+
+// Replay rebuilds a game by applying the recorded events in order. Replaying
+// a prefix of the events reconstructs the game as it was at that step. An
+// error is returned if the events do not match the cards the seed deals.
+func Replay(r Record) (*Game, error) {
+	players := make([]*player.Player, len(r.Players))
+	for i, name := range r.Players {
+		players[i] = player.NewPlayer(name)
+	}
+	g := NewSeededGame(players, r.Seed)
+	if r.Key != nil {
+		g = newKeyedGame(players, *r.Key)
+	}
+	g.Rules = r.Rules
+	for i, e := range r.Events {
+		if err := g.apply(e); err != nil {
+			return nil, fmt.Errorf("replaying event %d (%s): %w", i, e.Type, err)
+		}
+		g.log = append(g.log, e)
+	}
+	return g, nil
+}
+
+// apply changes the game state as an event describes. Once the game has
+// started it is the only place the state changes, which is what makes a game
+// replayable from its log.
+func (g *Game) apply(e Event) error {
+	switch e.Type {
+	case EventStage, EventReshuffle, EventShowdown, EventGameOver:
+	default:
+		if e.Player < 0 || e.Player >= len(g.Players) {
+			return ErrReplayMismatch
+		}
+	}
+
+	switch e.Type {
+	case EventNewHand:
+		g.dealer = e.Player
+		g.Deck = g.newDeck()
+		g.Deck.Shuffle()
+		g.Round = 0
+		g.tricks = 0
+		g.declarer = -1
+		g.failed = false
+		g.history = nil
+	case EventDeal:
+		g.Players[e.Player].Hand = []cards.Card{}
+		return g.draw(e.Player, e.Cards)
+	case EventStage:
+		g.Stage = e.Stage
+		g.acted = 0
+		g.turn = g.leftOfDealer()
+		if e.Stage == Trick && g.declarer != -1 {
+			g.turn = g.declarer
+		}
+		g.lead = g.turn
+		g.trick = make([]cards.Card, len(g.Players))
+		g.claims = make([]HandRank, len(g.Players))
+	case EventToss:
+		hand, ok := removeCards(g.Players[e.Player].Hand, e.Cards)
+		if !ok {
+			return ErrReplayMismatch
+		}
+		g.Players[e.Player].Hand = hand
+		g.Deck.Discard(e.Cards...)
+	case EventReshuffle:
+		g.Deck.Reshuffle()
+	case EventDraw:
+		if err := g.draw(e.Player, e.Cards); err != nil {
+			return err
+		}
+		g.nextTurn()
+	case EventStandPat:
+		g.nextTurn()
+	case EventShowdown:
+		g.Round++
+		g.acted = 0
+		g.turn = g.leftOfDealer()
+	case EventHandScored:
+		g.Players[e.Player].Score += e.Points
+	case EventDeclare:
+		g.declarer = e.Player
+	case EventPass:
+		g.nextTurn()
+	case EventClaim:
+		g.claims[e.Player] = e.Rank
+		g.nextTurn()
+	case EventFalseClaim:
+		g.claims[e.Player] = HighCard
+		g.Players[e.Player].Score += e.Points
+	case EventChicagoScored, EventBonusScored, EventBust:
+		g.Players[e.Player].Score += e.Points
+	case EventTrickPlay:
+		hand, ok := removeCards(g.Players[e.Player].Hand, e.Cards)
+		if !ok || len(e.Cards) != 1 {
+			return ErrReplayMismatch
+		}
+		g.Players[e.Player].Hand = hand
+		g.trick[e.Player] = e.Cards[0]
+		g.nextTurn()
+	case EventTrickWon:
+		if g.declarer != -1 && e.Player != g.declarer {
+			g.failed = true
+		}
+		g.history = append(g.history, PlayedTrick{Cards: g.trick, Lead: g.lead, Winner: e.Player})
+		g.tricks++
+		g.acted = 0
+		g.lead = e.Player
+		g.turn = e.Player
+		g.trick = make([]cards.Card, len(g.Players))
+	case EventTrickScored:
+		g.Players[e.Player].Score += e.Points
+	case EventGameOver:
+		g.Stage = Over
+	default:
+		return fmt.Errorf("unknown event type %q", e.Type)
+	}
+	return nil
+}
+
+// newDeck returns the deck of the next hand. The decks of a keyed game share
+// its source; those of a seeded game each get a seed of their own.
+func (g *Game) newDeck() *deck.Deck {
+	if g.source != nil {
+		return deck.NewDeck(g.source)
+	}
+	return deck.NewSeededDeck(g.rng.Int63())
+}
+
+// draw moves the expected cards from the deck into a player's hand.
+func (g *Game) draw(playerIndex int, expected []cards.Card) error {
+	drawn := g.Deck.DrawMultiple(len(expected))
+	for i := range expected {
+		if i >= len(drawn) || drawn[i] != expected[i] {
+			return ErrReplayMismatch
+		}
+	}
+	g.Players[playerIndex].Hand = append(g.Players[playerIndex].Hand, drawn...)
+	return nil
+}
+
+// leftOfDealer returns the player who acts first in every exchange and leads
+// the first trick.
+func (g *Game) leftOfDealer() int {
+	return (g.dealer + 1) % len(g.Players)
+}
+
+// nextTurn passes the turn to the next player.
+func (g *Game) nextTurn() {
+	g.acted++
+	g.turn = (g.turn + 1) % len(g.Players)
+}
+
+// removeCards returns hand without the given cards, keeping the order of the
+// rest. It reports false if any of the cards is not in the hand.
+func removeCards(hand []cards.Card, toRemove []cards.Card) ([]cards.Card, bool) {
+	remaining := copyCards(hand)
+	for _, card := range toRemove {
+		found := false
+		for i, held := range remaining {
+			if held == card {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return remaining, true
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+// Standing is one hand's place in a ranking. Place 1 is the best hand and
+// hands that compare equal share a place.
+type Standing struct {
+	Player     int
+	Evaluation HandEvaluation
+	Place      int
+}
+
+// RankHands orders hands from best to worst. Player is the hand's index in
+// hands, and tied hands keep their input order within a shared place. The
+// hands themselves are left untouched.
+func RankHands(hands [][]cards.Card) []Standing {
+	standings := make([]Standing, len(hands))
+	for i, hand := range hands {
+		standings[i] = Standing{Player: i, Evaluation: EvaluateHand(copyCards(hand))}
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return CompareEvaluations(standings[i].Evaluation, standings[j].Evaluation) > 0
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+		if i > 0 && CompareEvaluations(standings[i-1].Evaluation, standings[i].Evaluation) == 0 {
+			standings[i].Place = standings[i-1].Place
+		}
+	}
+	return standings
+}
+
+// Winners returns the standings sharing first place.
+func Winners(standings []Standing) []Standing {
+	winners := []Standing{}
+	for _, s := range standings {
+		if s.Place == 1 {
+			winners = append(winners, s)
+		}
+	}
+	return winners
+}
+
+// ResolveTie picks the players who score from the standings sharing first
+// place, which must be in play order.
+func ResolveTie(policy TiePolicy, winners []Standing) []Standing {
+	if len(winners) < 2 {
+		return winners
+	}
+	switch policy {
+	case TieSplit:
+		return winners
+	case TieSuit:
+		best := winners[0]
+		for _, s := range winners[1:] {
+			if compareSuits(s.Evaluation, best.Evaluation) > 0 {
+				best = s
+			}
+		}
+		return []Standing{best}
+	default:
+		return winners[:1]
+	}
+}
+
+// compareRanks compares two ordered card lists position by position.
+func compareRanks(cards1, cards2 []cards.Card) int {
+	for i := 0; i < len(cards1) && i < len(cards2); i++ {
+		if cards1[i].Rank != cards2[i].Rank {
+			return int(cards1[i].Rank) - int(cards2[i].Rank)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+func TestRankHands(t *testing.T) {
+	pairOfTwos := []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Two},
+		{Suit: cards.Spades, Rank: cards.Two},
+		{Suit: cards.Diamonds, Rank: cards.Four},
+		{Suit: cards.Spades, Rank: cards.Five},
+		{Suit: cards.Hearts, Rank: cards.Nine},
+	}
+	sameRanksOtherSuits := []cards.Card{
+		{Suit: cards.Clubs, Rank: cards.Two},
+		{Suit: cards.Diamonds, Rank: cards.Two},
+		{Suit: cards.Clubs, Rank: cards.Four},
+		{Suit: cards.Hearts, Rank: cards.Five},
+		{Suit: cards.Clubs, Rank: cards.Nine},
+	}
+	triple := []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Three},
+		{Suit: cards.Spades, Rank: cards.Three},
+		{Suit: cards.Clubs, Rank: cards.Three},
+		{Suit: cards.Spades, Rank: cards.Eight},
+		{Suit: cards.Hearts, Rank: cards.King},
+	}
+	highCard := []cards.Card{
+		{Suit: cards.Hearts, Rank: cards.Ace},
+		{Suit: cards.Spades, Rank: cards.King},
+		{Suit: cards.Clubs, Rank: cards.Seven},
+		{Suit: cards.Spades, Rank: cards.Six},
+		{Suit: cards.Diamonds, Rank: cards.Four},
+	}
+
+	standings := RankHands([][]cards.Card{highCard, pairOfTwos, triple, sameRanksOtherSuits})
+
+	expected := []struct {
+		player int
+		place  int
+		rank   HandRank
+	}{
+		{player: 2, place: 1, rank: Triple},
+		{player: 1, place: 2, rank: Pair},
+		{player: 3, place: 2, rank: Pair},
+		{player: 0, place: 4, rank: HighCard},
+	}
+	if len(standings) != len(expected) {
+		t.Fatalf("expected %d standings, got %d", len(expected), len(standings))
+	}
+	for i, want := range expected {
+		got := standings[i]
+		if got.Player != want.player || got.Place != want.place || got.Evaluation.Rank != want.rank {
+			t.Errorf("standing %d = player %d place %d %v, want player %d place %d %v",
+				i, got.Player, got.Place, got.Evaluation.Rank, want.player, want.place, want.rank)
+		}
+	}
+
+	winners := Winners(RankHands([][]cards.Card{pairOfTwos, sameRanksOtherSuits}))
+	if len(winners) != 2 {
+		t.Errorf("expected an explicit two-way tie, got %d winners", len(winners))
+	}
+	if pairOfTwos[4].Rank != cards.Nine {
+		t.Errorf("expected RankHands to leave the hands untouched, got %v", pairOfTwos)
+	}
+}
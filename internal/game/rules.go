@@ -0,0 +1,144 @@
+package game
+
+import "fmt"
+
+// MaxTableSize is the most players a table seats. Chicago is played by two
+// to eight; a single deck could serve one more, since after everyone has been
+// dealt the stock and discards together must still cover a full exchange of
+// one hand.
+const MaxTableSize = 8
+
+// TiePolicy decides who scores when the best hands at a showdown are exactly
+// equal in rank.
+type TiePolicy string
+
+const (
+	// TieSplit lets every tied player score the hand.
+	TieSplit TiePolicy = "split"
+	// TieSuit awards the hand to the highest suits, compared card by card
+	// with cards.SuitValue.
+	TieSuit TiePolicy = "suit"
+	// TieFirst awards the hand to the tied player who declared it first, that
+	// is the one earliest in play order.
+	TieFirst TiePolicy = "first"
+)
+
+// EndCondition decides how reaching the target score ends the game.
+type EndCondition string
+
+const (
+	// EndReach ends the game as soon as anyone reaches the target.
+	EndReach EndCondition = "reach"
+	// EndExact requires the target to be hit exactly: points past it are
+	// taken off again, so a player at 48 who scores 5 falls back to 47.
+	EndExact EndCondition = "exact"
+	// EndRoof stops every score but the last trick and a Chicago at the
+	// roof, so only winning the tricks can finish the game.
+	EndRoof EndCondition = "roof"
+)
+
+// Rules holds the house rules a game is played with. The end condition is
+// checked after every scoring event.
+type Rules struct {
+	MinPlayers   int          `json:"min_players"`
+	MaxPlayers   int          `json:"max_players"`
+	TargetScore  int          `json:"target_score"` // points that end the game
+	EndCondition EndCondition `json:"end_condition"`
+	Roof         int          `json:"roof,omitempty"` // highest score hands can reach with EndRoof
+	Exchanges    int          `json:"exchanges"`      // poker rounds before the tricks
+	Announce     bool         `json:"announce"`       // players claim their hands at showdowns
+	TiePolicy    TiePolicy    `json:"tie_policy"`
+	Scoring      ScoringRules `json:"scoring"`
+
+	// TrickBonuses are house rules scored at the end of every trick phase.
+	TrickBonuses []TrickBonus `json:"trick_bonuses,omitempty"`
+}
+
+// DefaultRules returns the rules used when nothing else is configured.
+func DefaultRules() Rules {
+	return Rules{
+		MinPlayers:   2,
+		MaxPlayers:   8,
+		TargetScore:  50,
+		EndCondition: EndReach,
+		Roof:         46,
+		Exchanges:    3,
+		TiePolicy:    TieSuit,
+		Scoring:      TraditionalScoring(),
+	}
+}
+
+// Validate reports the first rule that cannot be played.
+func (r Rules) Validate() error {
+	if r.MinPlayers < 2 {
+		return fmt.Errorf("min players must be at least 2, got %d", r.MinPlayers)
+	}
+	if r.MaxPlayers < r.MinPlayers {
+		return fmt.Errorf("max players (%d) must not be below min players (%d)", r.MaxPlayers, r.MinPlayers)
+	}
+	if r.MaxPlayers > MaxTableSize {
+		return fmt.Errorf("max players must be at most %d, got %d", MaxTableSize, r.MaxPlayers)
+	}
+	if r.TargetScore < 1 {
+		return fmt.Errorf("target score must be at least 1, got %d", r.TargetScore)
+	}
+	switch r.EndCondition {
+	case EndReach, EndExact:
+	case EndRoof:
+		if r.Roof < 1 || r.Roof >= r.TargetScore {
+			return fmt.Errorf("roof must be between 1 and the target score (%d), got %d", r.TargetScore, r.Roof)
+		}
+	default:
+		return fmt.Errorf("unknown end condition %q, expected %q, %q or %q", r.EndCondition, EndReach, EndExact, EndRoof)
+	}
+	if r.Exchanges < 1 {
+		return fmt.Errorf("exchanges must be at least 1, got %d", r.Exchanges)
+	}
+	switch r.TiePolicy {
+	case TieSplit, TieSuit, TieFirst:
+	default:
+		return fmt.Errorf("unknown tie policy %q, expected %q, %q or %q", r.TiePolicy, TieSplit, TieSuit, TieFirst)
+	}
+	if err := r.Scoring.Validate(); err != nil {
+		return fmt.Errorf("scoring %q: %w", r.Scoring.Name, err)
+	}
+	for _, bonus := range r.TrickBonuses {
+		if err := bonus.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Summary describes the rules in one line for players joining a table.
+func (r Rules) Summary() string {
+	s := r.Scoring
+	announce := ""
+	if r.Announce {
+		announce = ", hands are announced"
+	}
+	return fmt.Sprintf("First to %d points%s, %d exchanges per hand%s, %d-%d players, ties: %s. "+
+		"Scoring (%s): pair %d, two pair %d, triple %d, straight %d, flush %d, full house %d, "+
+		"four of a kind %d, straight flush %d, royal straight flush %d, last trick %d, chicago %d, false claim -%d.",
+		r.TargetScore, r.endSummary(), r.Exchanges, announce, r.MinPlayers, r.MaxPlayers, r.TiePolicy,
+		s.Name, s.Pair, s.TwoPair, s.Triple, s.Straight, s.Flush, s.FullHouse,
+		s.FourOfAKind, s.StraightFlush, s.RoyalStraightFlush, s.LastTrick, s.Chicago, s.FalseClaim) + r.bonusSummary()
+}
+
+func (r Rules) endSummary() string {
+	switch r.EndCondition {
+	case EndExact:
+		return " exactly"
+	case EndRoof:
+		return fmt.Sprintf(" (hands score up to %d, only the tricks finish)", r.Roof)
+	}
+	return ""
+}
+
+func (r Rules) bonusSummary() string {
+	summary := ""
+	for _, bonus := range r.TrickBonuses {
+		summary += fmt.Sprintf(" Whoever %s scores %+d.", trickHooks[bonus.Hook].Description, bonus.Points)
+	}
+	return summary
+}
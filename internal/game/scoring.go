@@ -0,0 +1,142 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScoringRules is a named points table: what each poker hand is worth at a
+// showdown, what winning the last trick is worth, what a Chicago wins or
+// costs and what claiming a hand one does not hold costs.
+type ScoringRules struct {
+	Name               string `json:"name"`
+	Pair               int    `json:"pair"`
+	TwoPair            int    `json:"two_pair"`
+	Triple             int    `json:"triple"`
+	Straight           int    `json:"straight"`
+	Flush              int    `json:"flush"`
+	FullHouse          int    `json:"full_house"`
+	FourOfAKind        int    `json:"four_of_a_kind"`
+	StraightFlush      int    `json:"straight_flush"`
+	RoyalStraightFlush int    `json:"royal_straight_flush"`
+	LastTrick          int    `json:"last_trick"`
+	Chicago            int    `json:"chicago"`
+	FalseClaim         int    `json:"false_claim"`
+}
+
+// TraditionalScoring is the Swedish table most clubs play with.
+func TraditionalScoring() ScoringRules {
+	return ScoringRules{
+		Name:               "traditional",
+		Pair:               1,
+		TwoPair:            2,
+		Triple:             3,
+		Straight:           4,
+		Flush:              5,
+		FullHouse:          6,
+		FourOfAKind:        8,
+		StraightFlush:      52,
+		RoyalStraightFlush: 52,
+		LastTrick:          5,
+		Chicago:            15,
+		FalseClaim:         5,
+	}
+}
+
+// ClassicScoring is the table this game was first written with: one point
+// per hand rank and a small reward for the last trick.
+func ClassicScoring() ScoringRules {
+	return ScoringRules{
+		Name:               "classic",
+		Pair:               1,
+		TwoPair:            2,
+		Triple:             3,
+		Straight:           4,
+		Flush:              5,
+		FullHouse:          6,
+		FourOfAKind:        7,
+		StraightFlush:      8,
+		RoyalStraightFlush: 52,
+		LastTrick:          3,
+		Chicago:            15,
+		FalseClaim:         3,
+	}
+}
+
+var scoringPresets = map[string]func() ScoringRules{
+	"traditional": TraditionalScoring,
+	"classic":     ClassicScoring,
+}
+
+// ScoringPreset returns the built-in scoring table with the given name.
+func ScoringPreset(name string) (ScoringRules, error) {
+	preset, ok := scoringPresets[name]
+	if !ok {
+		return ScoringRules{}, fmt.Errorf("unknown scoring preset %q, expected one of %s", name, strings.Join(ScoringPresetNames(), ", "))
+	}
+	return preset(), nil
+}
+
+// ScoringPresetNames lists the built-in scoring tables in alphabetical order.
+func ScoringPresetNames() []string {
+	names := make([]string, 0, len(scoringPresets))
+	for name := range scoringPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HandPoints returns what a hand of the given rank scores at a showdown. A
+// high card never scores.
+func (s ScoringRules) HandPoints(rank HandRank) int {
+	switch rank {
+	case Pair:
+		return s.Pair
+	case TwoPair:
+		return s.TwoPair
+	case Triple:
+		return s.Triple
+	case Straight:
+		return s.Straight
+	case Flush:
+		return s.Flush
+	case FullHouse:
+		return s.FullHouse
+	case FourOfAKind:
+		return s.FourOfAKind
+	case StraightFlush:
+		return s.StraightFlush
+	case RoyalStraightFlush:
+		return s.RoyalStraightFlush
+	default:
+		return 0
+	}
+}
+
+// Validate reports the first entry of the table that cannot be played: points
+// must be positive and a better hand must never score less than a worse one.
+func (s ScoringRules) Validate() error {
+	previous := HighCard
+	for rank := Pair; rank <= RoyalStraightFlush; rank++ {
+		points := s.HandPoints(rank)
+		if points <= 0 {
+			return fmt.Errorf("%v must score at least 1 point, got %d", rank, points)
+		}
+		if points < s.HandPoints(previous) {
+			return fmt.Errorf("%v (%d points) must not score less than %v (%d points)", rank, points, previous, s.HandPoints(previous))
+		}
+		previous = rank
+	}
+	if s.LastTrick <= 0 {
+		return fmt.Errorf("last trick must score at least 1 point, got %d", s.LastTrick)
+	}
+	if s.Chicago <= 0 {
+		return fmt.Errorf("chicago must score at least 1 point, got %d", s.Chicago)
+	}
+	if s.FalseClaim < 0 {
+		return fmt.Errorf("false claim penalty must not be negative, got %d", s.FalseClaim)
+	}
+	return nil
+}
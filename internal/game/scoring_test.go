@@ -0,0 +1,61 @@
+package game
+
+import "testing"
+
+func TestScoringPresets(t *testing.T) {
+	for _, name := range ScoringPresetNames() {
+		scoring, err := ScoringPreset(name)
+		if err != nil {
+			t.Fatalf("ScoringPreset(%q) error = %v", name, err)
+		}
+		if scoring.Name != name {
+			t.Errorf("ScoringPreset(%q) is named %q", name, scoring.Name)
+		}
+		if err := scoring.Validate(); err != nil {
+			t.Errorf("preset %q is invalid: %v", name, err)
+		}
+	}
+	if _, err := ScoringPreset("vegas"); err == nil {
+		t.Error("expected an unknown preset to be rejected")
+	}
+
+	traditional := TraditionalScoring()
+	want := map[HandRank]int{
+		HighCard: 0, Pair: 1, TwoPair: 2, Triple: 3, Straight: 4, Flush: 5,
+		FullHouse: 6, FourOfAKind: 8, StraightFlush: 52, RoyalStraightFlush: 52,
+	}
+	for rank, points := range want {
+		if got := traditional.HandPoints(rank); got != points {
+			t.Errorf("traditional %v = %d points, want %d", rank, got, points)
+		}
+	}
+	if traditional.LastTrick != 5 {
+		t.Errorf("traditional last trick = %d points, want 5", traditional.LastTrick)
+	}
+}
+
+func TestScoringRulesValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*ScoringRules)
+	}{
+		{name: "ZeroPair", modify: func(s *ScoringRules) { s.Pair = 0 }},
+		{name: "FlushBelowStraight", modify: func(s *ScoringRules) { s.Flush = s.Straight - 1 }},
+		{name: "NoLastTrick", modify: func(s *ScoringRules) { s.LastTrick = 0 }},
+		{name: "NoChicago", modify: func(s *ScoringRules) { s.Chicago = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scoring := TraditionalScoring()
+			tt.modify(&scoring)
+			if err := scoring.Validate(); err == nil {
+				t.Error("expected the table to be rejected")
+			}
+			rules := DefaultRules()
+			rules.Scoring = scoring
+			if err := rules.Validate(); err == nil {
+				t.Error("expected rules with the table to be rejected")
+			}
+		})
+	}
+}
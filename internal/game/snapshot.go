@@ -0,0 +1,67 @@
+package game
+
+import "github.com/antongollbo123/chicago-poker/pkg/cards"
+
+// SeatView is what everyone at the table can see of a player.
+type SeatView struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+	Cards int    `json:"cards"`
+}
+
+// Snapshot is the state of the game as one player sees it: everything public
+// plus their own hand. Seat is -1 for an onlooker, who sees no hand.
+type Snapshot struct {
+	Seat       int           `json:"seat"`
+	Stage      Stage         `json:"stage"`
+	Round      int           `json:"round"`
+	Dealer     int           `json:"dealer"`
+	Turn       int           `json:"turn"`
+	Lead       int           `json:"lead"`
+	Declarer   int           `json:"declarer"`
+	BestClaim  HandRank      `json:"best_claim,omitempty"`
+	Players    []SeatView    `json:"players"`
+	Hand       []cards.Card  `json:"hand,omitempty"`
+	LegalPlays []int         `json:"legal_plays,omitempty"`
+	Trick      []cards.Card  `json:"trick,omitempty"`
+	Tricks     []PlayedTrick `json:"tricks,omitempty"`
+	Rules      Rules         `json:"rules"`
+}
+
+// Snapshot returns the game as the player in the given seat sees it.
+func (g *Game) Snapshot(seat int) Snapshot {
+	s := Snapshot{
+		Seat:      -1,
+		Stage:     g.Stage,
+		Round:     g.Round,
+		Dealer:    g.dealer,
+		Turn:      g.turn,
+		Lead:      g.lead,
+		Declarer:  g.declarer,
+		BestClaim: g.BestClaim(),
+		Players:   make([]SeatView, len(g.Players)),
+		Rules:     g.Rules,
+	}
+	for i, player := range g.Players {
+		s.Players[i] = SeatView{Name: player.Name, Score: player.Score, Cards: len(player.Hand)}
+	}
+	if g.Stage == Trick {
+		s.Trick = g.CurrentTrick()
+		s.Tricks = g.TrickHistory()
+	}
+	if seat >= 0 && seat < len(g.Players) {
+		s.Seat = seat
+		s.Hand = copyCards(g.Players[seat].Hand)
+		s.LegalPlays = g.LegalPlays(seat)
+	}
+	return s
+}
+
+// Redact returns the event as players other than its own see it: the cards
+// of private events are left out.
+func (e Event) Redact() Event {
+	if e.Private() {
+		e.Cards = nil
+	}
+	return e
+}
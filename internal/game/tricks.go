@@ -0,0 +1,105 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+// PlayedTrick is a completed trick: the cards played, indexed by player, who
+// led it and who won it.
+type PlayedTrick struct {
+	Cards  []cards.Card `json:"cards"`
+	Lead   int          `json:"lead"`
+	Winner int          `json:"winner"`
+}
+
+// TrickHook is a house rule scored once all the tricks of a hand have been
+// played. Players returns the players the rule applies to, given the hand's
+// tricks in order; each of them scores the points the rules configure for
+// the hook, which are negative for a penalty.
+type TrickHook struct {
+	Description string
+	Players     func(tricks []PlayedTrick) []int
+}
+
+// TrickBonus enables a registered trick hook in the rules.
+type TrickBonus struct {
+	Hook   string `json:"hook"`
+	Points int    `json:"points"`
+}
+
+var trickHooks = map[string]TrickHook{
+	"last_trick_deuce": {
+		Description: "won the last trick with a two",
+		Players:     lastTrickDeuce,
+	},
+	"last_trick_ace_lead": {
+		Description: "led the last trick with an ace",
+		Players:     lastTrickAceLead,
+	},
+}
+
+// RegisterTrickHook makes a hook available to the rules under name. Hooks
+// must be registered before any rules using them are validated.
+func RegisterTrickHook(name string, hook TrickHook) {
+	trickHooks[name] = hook
+}
+
+// TrickHookNames lists the registered trick hooks in alphabetical order.
+func TrickHookNames() []string {
+	names := make([]string, 0, len(trickHooks))
+	for name := range trickHooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate reports whether the bonus names a registered hook and is worth
+// any points.
+func (b TrickBonus) Validate() error {
+	if _, ok := trickHooks[b.Hook]; !ok {
+		return fmt.Errorf("unknown trick hook %q, expected one of %v", b.Hook, TrickHookNames())
+	}
+	if b.Points == 0 {
+		return fmt.Errorf("trick hook %q must be worth some points", b.Hook)
+	}
+	return nil
+}
+
+func lastTrickDeuce(tricks []PlayedTrick) []int {
+	if len(tricks) == 0 {
+		return nil
+	}
+	last := tricks[len(tricks)-1]
+	if last.Cards[last.Winner].Rank != cards.Two {
+		return nil
+	}
+	return []int{last.Winner}
+}
+
+func lastTrickAceLead(tricks []PlayedTrick) []int {
+	if len(tricks) == 0 {
+		return nil
+	}
+	last := tricks[len(tricks)-1]
+	if last.Cards[last.Lead].Rank != cards.Ace {
+		return nil
+	}
+	return []int{last.Lead}
+}
+
+// scoreTrickHooks awards the points of every enabled hook at the end of the
+// trick phase and reports whether they ended the game.
+func (g *Game) scoreTrickHooks() bool {
+	for _, bonus := range g.Rules.TrickBonuses {
+		for _, player := range trickHooks[bonus.Hook].Players(g.history) {
+			if g.award(Event{Type: EventBonusScored, Player: player, Points: bonus.Points, Hook: bonus.Hook}) {
+				return true
+			}
+		}
+	}
+	return false
+}
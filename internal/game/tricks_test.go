@@ -0,0 +1,74 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+func TestTrickHooks(t *testing.T) {
+	early := PlayedTrick{
+		Cards:  []cards.Card{{Suit: cards.Hearts, Rank: cards.Two}, {Suit: cards.Hearts, Rank: cards.Ace}},
+		Lead:   1,
+		Winner: 1,
+	}
+	deuceWins := PlayedTrick{
+		Cards:  []cards.Card{{Suit: cards.Spades, Rank: cards.Two}, {Suit: cards.Hearts, Rank: cards.King}},
+		Lead:   0,
+		Winner: 0,
+	}
+	aceLeads := PlayedTrick{
+		Cards:  []cards.Card{{Suit: cards.Clubs, Rank: cards.Three}, {Suit: cards.Diamonds, Rank: cards.Ace}},
+		Lead:   1,
+		Winner: 1,
+	}
+
+	tests := []struct {
+		hook   string
+		tricks []PlayedTrick
+		want   []int
+	}{
+		{hook: "last_trick_deuce", tricks: []PlayedTrick{early, deuceWins}, want: []int{0}},
+		{hook: "last_trick_deuce", tricks: []PlayedTrick{deuceWins, early}, want: nil},
+		{hook: "last_trick_deuce", tricks: nil, want: nil},
+		{hook: "last_trick_ace_lead", tricks: []PlayedTrick{deuceWins, aceLeads}, want: []int{1}},
+		{hook: "last_trick_ace_lead", tricks: []PlayedTrick{aceLeads, deuceWins}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hook, func(t *testing.T) {
+			if got := trickHooks[tt.hook].Players(tt.tricks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s(%v) = %v, want %v", tt.hook, tt.tricks, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrickBonusValidate(t *testing.T) {
+	if err := (TrickBonus{Hook: "last_trick_deuce", Points: 5}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (TrickBonus{Hook: "last_trick_seven", Points: 5}).Validate(); err == nil {
+		t.Error("expected an unknown hook to be rejected")
+	}
+	if err := (TrickBonus{Hook: "last_trick_deuce"}).Validate(); err == nil {
+		t.Error("expected a bonus worth nothing to be rejected")
+	}
+
+	RegisterTrickHook("test_every_winner", TrickHook{
+		Description: "won a trick",
+		Players: func(tricks []PlayedTrick) []int {
+			winners := []int{}
+			for _, trick := range tricks {
+				winners = append(winners, trick.Winner)
+			}
+			return winners
+		},
+	})
+	defer delete(trickHooks, "test_every_winner")
+	rules := DefaultRules()
+	rules.TrickBonuses = []TrickBonus{{Hook: "test_every_winner", Points: 1}}
+	if err := rules.Validate(); err != nil {
+		t.Errorf("expected a registered hook to be accepted, got %v", err)
+	}
+}
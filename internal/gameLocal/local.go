@@ -0,0 +1,193 @@
+package gameLocal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+// clearScreen is the ANSI sequence that clears a terminal and moves the
+// cursor to its top.
+const clearScreen = "\033[H\033[2J"
+
+// Run plays a game on a single terminal, reading every player's moves from
+// in and printing the table to out. Every hand is shown to whoever is
+// looking, which suits trying the rules out alone.
+func Run(g *game.Game, in io.Reader, out io.Writer) error {
+	return play(g, in, out, false)
+}
+
+// RunHotSeat plays a game on a terminal the players pass around. Whenever
+// the turn goes to another player the screen is cleared and they are asked
+// to press Enter once they have the terminal, before their hand is shown.
+// The screen in between only shows what everyone may see: what happened
+// since, the cards on the table and the scores.
+func RunHotSeat(g *game.Game, in io.Reader, out io.Writer) error {
+	return play(g, in, out, true)
+}
+
+func play(g *game.Game, in io.Reader, out io.Writer, hotSeat bool) error {
+	events, err := g.Start()
+	if err != nil {
+		return err
+	}
+	// With a hot seat, events wait to be shown to whoever takes the terminal
+	var news []string
+	show := func(events []game.Event) {
+		if hotSeat {
+			news = append(news, describe(g, events)...)
+		} else {
+			printEvents(g, out, events)
+		}
+	}
+	show(events)
+
+	scanner := bufio.NewScanner(in)
+	showing := -1 // player whose hand may be on the screen
+	for g.Stage != game.Over {
+		playerIndex := g.Turn()
+		player := g.Players[playerIndex]
+		if hotSeat {
+			if playerIndex != showing {
+				if err := handOff(g, scanner, out, news, playerIndex); err != nil {
+					return err
+				}
+				showing = playerIndex
+			} else {
+				printLines(out, news)
+			}
+			news = nil
+		}
+
+		action := game.Action{Player: playerIndex}
+		switch g.Stage {
+		case game.Exchange:
+			fmt.Fprintf(out, "Player %s, your hand is: %v\n", player.Name, player.Hand)
+			fmt.Fprintf(out, "Enter the indices of the cards you want to toss, separated by spaces, or nothing to stand pat: ")
+			action.Type = game.ActionToss
+		case game.Showdown:
+			fmt.Fprintf(out, "Player %s, your hand is: %v\n", player.Name, player.Hand)
+			if best := g.BestClaim(); best != game.HighCard {
+				fmt.Fprintf(out, "Best claim so far: %v\n", best)
+			}
+			fmt.Fprintf(out, "Announce your hand (e.g. pair, two pair, flush), or nothing to pass: ")
+			action.Type = game.ActionClaim
+		case game.Chicago:
+			fmt.Fprintf(out, "Player %s, your hand is: %v\n", player.Name, player.Hand)
+			fmt.Fprintf(out, "Declare Chicago and claim all five tricks? (y/N): ")
+			action.Type = game.ActionPass
+		case game.Trick:
+			if g.CurrentTrick()[g.Lead()] == (cards.Card{}) {
+				fmt.Fprintf(out, "Starting trick %d\n", g.TrickNumber()+1)
+			}
+			fmt.Fprintf(out, "Player %s, your hand is: %v\n", player.Name, player.Hand)
+			fmt.Fprintf(out, "Enter the index of the card you want to play: ")
+			action.Type = game.ActionPlay
+		}
+
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return err
+			}
+			return io.ErrUnexpectedEOF
+		}
+		switch action.Type {
+		case game.ActionClaim:
+			if strings.TrimSpace(scanner.Text()) == "" {
+				action.Type = game.ActionPass
+			}
+			action.Rank, _ = game.ParseHandRank(scanner.Text())
+		case game.ActionPass:
+			if game.ParseYes(scanner.Text()) {
+				action.Type = game.ActionDeclare
+			}
+		default:
+			action.Cards = game.ParseInput(scanner.Text())
+			if action.Type == game.ActionToss && len(action.Cards) == 0 {
+				action.Type = game.ActionStandPat
+			}
+		}
+
+		events, err := g.Apply(action)
+		if err != nil {
+			fmt.Fprintf(out, "Invalid move: %v. Try again.\n", err)
+			continue
+		}
+		show(events)
+	}
+	if hotSeat {
+		fmt.Fprint(out, clearScreen)
+		printLines(out, news)
+		fmt.Fprintln(out)
+		printTable(g, out)
+	}
+	return nil
+}
+
+// handOff clears the screen, tells the table what happened since the last
+// player had it, and waits for the next player to take the terminal.
+func handOff(g *game.Game, scanner *bufio.Scanner, out io.Writer, news []string, playerIndex int) error {
+	fmt.Fprint(out, clearScreen)
+	printLines(out, news)
+	if len(news) > 0 {
+		fmt.Fprintln(out)
+	}
+	printTable(g, out)
+	fmt.Fprintf(out, "\nPass the terminal to %s and press Enter.", g.Players[playerIndex].Name)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return io.ErrUnexpectedEOF
+	}
+	fmt.Fprint(out, clearScreen)
+	printTable(g, out)
+	return nil
+}
+
+// printTable shows what everyone at the table can see: the scores, the best
+// hand claimed at a showdown and the cards played to the current trick.
+func printTable(g *game.Game, out io.Writer) {
+	scores := make([]string, len(g.Players))
+	for i, p := range g.Players {
+		scores[i] = fmt.Sprintf("%s %d", p.Name, p.Score)
+	}
+	fmt.Fprintf(out, "Scores: %s (%s deals)\n", strings.Join(scores, ", "), g.Players[g.Dealer()].Name)
+	switch g.Stage {
+	case game.Showdown:
+		if best := g.BestClaim(); best != game.HighCard {
+			fmt.Fprintf(out, "Best claim so far: %v\n", best)
+		}
+	case game.Trick:
+		trick := g.CurrentTrick()
+		for i := range trick {
+			seat := (g.Lead() + i) % len(trick)
+			if trick[seat] != (cards.Card{}) {
+				fmt.Fprintf(out, "On the table: %s played %v\n", g.Players[seat].Name, trick[seat])
+			}
+		}
+	}
+}
+
+func printEvents(g *game.Game, out io.Writer, events []game.Event) {
+	printLines(out, describe(g, events))
+}
+
+func printLines(out io.Writer, lines []string) {
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+}
+
+// describe renders events in their public form.
+func describe(g *game.Game, events []game.Event) []string {
+	lines := make([]string, len(events))
+	for i, e := range events {
+		lines[i] = g.Describe(e)
+	}
+	return lines
+}
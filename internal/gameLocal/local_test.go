@@ -0,0 +1,62 @@
+package gameLocal
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+)
+
+func newGame() *game.Game {
+	return game.NewSeededGame([]*player.Player{player.NewPlayer("Ada"), player.NewPlayer("Bo")}, 3)
+}
+
+func TestHotSeatHidesHandsBetweenPlayers(t *testing.T) {
+	// Enter both passes the terminal on and stands pat, through the exchanges
+	var out bytes.Buffer
+	err := RunHotSeat(newGame(), strings.NewReader(strings.Repeat("\n", 12)), &out)
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("RunHotSeat() error = %v, want it to run out of input", err)
+	}
+
+	screens := strings.Split(out.String(), clearScreen)
+	hands := 0
+	for i, screen := range screens {
+		var shown []string
+		for _, name := range []string{"Ada", "Bo"} {
+			if strings.Contains(screen, "Player "+name+", your hand is") {
+				shown = append(shown, name)
+			}
+		}
+		if len(shown) == 0 {
+			continue
+		}
+		hands++
+		if len(shown) > 1 {
+			t.Fatalf("screen %d shows the hands of %v:\n%s", i, shown, screen)
+		}
+		if want := "Pass the terminal to " + shown[0]; !strings.Contains(screens[i-1], want) {
+			t.Errorf("the hand of %s was shown without asking to pass the terminal to them:\n%s", shown[0], screens[i-1])
+		}
+	}
+	if hands < 4 {
+		t.Errorf("only %d hands were shown:\n%s", hands, out.String())
+	}
+}
+
+func TestOpenPlayNeverClearsTheScreen(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(newGame(), strings.NewReader(strings.Repeat("\n", 6)), &out)
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("Run() error = %v, want it to run out of input", err)
+	}
+	if strings.Contains(out.String(), clearScreen) {
+		t.Error("Run() cleared the screen")
+	}
+	if !strings.Contains(out.String(), "Player Ada, your hand is") || !strings.Contains(out.String(), "Player Bo, your hand is") {
+		t.Errorf("Run() did not show both hands:\n%s", out.String())
+	}
+}
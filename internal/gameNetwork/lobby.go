@@ -0,0 +1,348 @@
+package gameNetwork
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxTableName bounds the length of table names.
+const maxTableName = 24
+
+const lobbyHelp = `Lobby commands:
+  list                         show the tables
+  create <name> [max players]  open a table and sit down at it
+  join <name>                  sit down at a table
+  ready                        vote to start the game at your table
+  leave                        get up from your table
+  resume <token>               take back your seat after losing your connection
+  back                         make your own moves again after being marked away
+  help                         show this help`
+
+var errNoTable = errors.New("you are not at a table")
+
+// handleInput acts on a line a client sent: lobby commands are handled here
+// and everything else goes to the game at their table.
+func (s *GameServer) handleInput(c *Client, line string) {
+	if c.json {
+		s.handleMessage(c, line)
+		return
+	}
+
+	fields := strings.Fields(line)
+	command := ""
+	if len(fields) > 0 {
+		command = strings.ToLower(fields[0])
+	}
+	var err error
+	switch command {
+	case "list":
+		s.listTables(c)
+	case "create":
+		if len(fields) < 2 || len(fields) > 3 {
+			err = errors.New("usage: create <name> [max players]")
+			break
+		}
+		maxPlayers := 0
+		if len(fields) == 3 {
+			if maxPlayers, err = strconv.Atoi(fields[2]); err != nil {
+				err = fmt.Errorf("max players must be a number, got %q", fields[2])
+				break
+			}
+		}
+		err = s.createTable(c, fields[1], maxPlayers)
+	case "join":
+		if len(fields) != 2 {
+			err = errors.New("usage: join <name>")
+			break
+		}
+		err = s.joinTable(c, fields[1])
+	case "leave":
+		err = s.leaveTable(c)
+	case "ready":
+		err = s.voteReady(c)
+	case "resume":
+		if len(fields) != 2 {
+			err = errors.New("usage: resume <token>")
+			break
+		}
+		err = s.resumeSeat(c, fields[1])
+	case "back":
+		err = s.comeBack(c)
+	case "help":
+		c.info(lobbyHelp)
+	default:
+		if s.playing(c) {
+			c.deliver(line)
+		} else if line != "" {
+			c.info("Unknown command.\n" + lobbyHelp)
+		}
+	}
+	if err != nil {
+		c.fail(0, err)
+	}
+}
+
+// handleMessage acts on a line from a JSON client.
+func (s *GameServer) handleMessage(c *Client, line string) {
+	var msg incoming
+	if err := json.Unmarshal([]byte(line), &msg); err != nil {
+		c.fail(0, fmt.Errorf("invalid message: %v", err))
+		return
+	}
+	var data TableData
+	var seat SeatData
+	var err error
+	switch msg.MoveType {
+	case CreateTable, JoinTable:
+		err = json.Unmarshal(msg.Data, &data)
+	case Resume:
+		err = json.Unmarshal(msg.Data, &seat)
+	}
+	if err != nil {
+		c.fail(msg.ID, fmt.Errorf("invalid %s: %v", msg.MoveType, err))
+		return
+	}
+
+	switch msg.MoveType {
+	case PlayerAction:
+		if !s.playing(c) {
+			err = errors.New("you are not playing at a table")
+			break
+		}
+		c.deliver(line)
+	case ListTables:
+		s.listTables(c)
+	case CreateTable:
+		err = s.createTable(c, data.Name, data.MaxPlayers)
+	case JoinTable:
+		err = s.joinTable(c, data.Name)
+	case LeaveTable:
+		err = s.leaveTable(c)
+	case Ready:
+		err = s.voteReady(c)
+	case Resume:
+		err = s.resumeSeat(c, seat.Token)
+	case Back:
+		err = s.comeBack(c)
+	default:
+		err = fmt.Errorf("unknown message type %q", msg.MoveType)
+	}
+	if err != nil {
+		c.fail(msg.ID, err)
+	}
+}
+
+// listTables sends a client the tables in the lobby.
+func (s *GameServer) listTables(c *Client) {
+	s.mu.Lock()
+	infos := make([]TableInfo, 0, len(s.tables))
+	for _, t := range s.tables {
+		infos = append(infos, t.info())
+	}
+	s.mu.Unlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	if c.json {
+		c.send(Message{MoveType: Tables, Data: infos})
+		return
+	}
+	if len(infos) == 0 {
+		c.info("No tables yet. Open one with: create <name> [max players]")
+		return
+	}
+	text := "Tables:"
+	for _, info := range infos {
+		text += "\n  " + describeTable(info)
+	}
+	c.info(text)
+}
+
+func describeTable(info TableInfo) string {
+	text := fmt.Sprintf("%s: %d/%d players", info.Name, len(info.Players), info.MaxPlayers)
+	if len(info.Players) > 0 {
+		text += " (" + strings.Join(info.Players, ", ") + ")"
+	}
+	if info.Started {
+		return text + ", playing"
+	}
+	return text + fmt.Sprintf(", %d ready", info.Ready)
+}
+
+// createTable opens a table and seats the client at it.
+func (s *GameServer) createTable(c *Client, name string, maxPlayers int) error {
+	if err := validTableName(name); err != nil {
+		return err
+	}
+	rules := s.Rules
+	if maxPlayers != 0 {
+		if maxPlayers < rules.MinPlayers || maxPlayers > rules.MaxPlayers {
+			return fmt.Errorf("tables seat %d to %d players", rules.MinPlayers, rules.MaxPlayers)
+		}
+		rules.MaxPlayers = maxPlayers
+	}
+
+	s.mu.Lock()
+	if c.table != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("you are already at table %s", c.table.Name)
+	}
+	if _, ok := s.tables[name]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("there is already a table named %s", name)
+	}
+	if s.closing {
+		s.mu.Unlock()
+		return errShuttingDown
+	}
+	if len(s.tables) >= s.MaxTables {
+		s.mu.Unlock()
+		return fmt.Errorf("the lobby is full, it holds %d tables", s.MaxTables)
+	}
+	s.tables[name] = newTable(s, name, rules)
+	s.mu.Unlock()
+	fmt.Printf("Player %s opened table %s\n", c.name, name)
+	return s.joinTable(c, name)
+}
+
+func validTableName(name string) error {
+	if name == "" {
+		return errors.New("tables need a name")
+	}
+	if len(name) > maxTableName {
+		return fmt.Errorf("table names are at most %d characters", maxTableName)
+	}
+	for _, r := range name {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return errors.New("table names are a single word")
+		}
+	}
+	return nil
+}
+
+// joinTable seats the client at the named table.
+func (s *GameServer) joinTable(c *Client, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.table != nil {
+		return fmt.Errorf("you are already at table %s", c.table.Name)
+	}
+	if s.closing {
+		return errShuttingDown
+	}
+	t, ok := s.tables[name]
+	if !ok {
+		return fmt.Errorf("there is no table named %s", name)
+	}
+	if err := t.join(c); err != nil {
+		return fmt.Errorf("cannot join table %s: %v", name, err)
+	}
+	c.table = t
+	return nil
+}
+
+// leaveTable gets the client up from their table. If its game is running,
+// moves are made for them until it ends.
+func (s *GameServer) leaveTable(c *Client) error {
+	s.mu.Lock()
+	t := c.table
+	if t == nil {
+		s.mu.Unlock()
+		return errNoTable
+	}
+	c.table = nil
+	if t.leave(c) {
+		delete(s.tables, t.Name)
+	}
+	s.mu.Unlock()
+
+	s.backToLobby(c, t)
+	return nil
+}
+
+// voteReady records the client's vote to start the game at their table.
+func (s *GameServer) voteReady(c *Client) error {
+	s.mu.Lock()
+	t := c.table
+	s.mu.Unlock()
+	if t == nil {
+		return errNoTable
+	}
+	return t.vote(c)
+}
+
+// resumeSeat gives a client back the seat a resume token was issued for.
+func (s *GameServer) resumeSeat(c *Client, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.table != nil {
+		return fmt.Errorf("you are already at table %s", c.table.Name)
+	}
+	if s.closing {
+		return errShuttingDown
+	}
+	for _, t := range s.tables {
+		found, err := t.resume(c, token)
+		if err != nil {
+			return err
+		}
+		if found {
+			c.table = t
+			return nil
+		}
+	}
+	return errBadToken
+}
+
+// comeBack tells the client's table that they are back after being marked
+// away.
+func (s *GameServer) comeBack(c *Client) error {
+	s.mu.Lock()
+	t := c.table
+	s.mu.Unlock()
+	if t == nil {
+		return errNoTable
+	}
+	return t.back(c)
+}
+
+// playing reports whether the client sits at a table whose game is running.
+func (s *GameServer) playing(c *Client) bool {
+	s.mu.Lock()
+	t := c.table
+	s.mu.Unlock()
+	return t != nil && t.isStarted()
+}
+
+// closeTable removes a table whose game is over and sends its players back
+// to the lobby.
+func (s *GameServer) closeTable(t *Table) {
+	s.mu.Lock()
+	delete(s.tables, t.Name)
+	var players []*Client
+	for _, c := range t.members() {
+		if c != nil && c.table == t {
+			c.table = nil
+			players = append(players, c)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range players {
+		s.backToLobby(c, t)
+	}
+}
+
+// backToLobby tells a client they have left a table and what else is open.
+func (s *GameServer) backToLobby(c *Client, t *Table) {
+	if c.json {
+		c.send(Message{PlayerName: c.name, MoveType: LeftTable, Data: TableData{Name: t.Name}})
+	} else {
+		c.info(fmt.Sprintf("You are back in the lobby, having left table %s.", t.Name))
+	}
+	s.listTables(c)
+}
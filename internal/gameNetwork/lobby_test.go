@@ -0,0 +1,138 @@
+package gameNetwork
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// expectError reads until the player is sent an error and checks that it
+// mentions want.
+func expectError(t *testing.T, p *testPlayer, want string) {
+	t.Helper()
+	for {
+		msg, err := p.next()
+		if err != nil {
+			t.Fatalf("%s: waiting for an error about %q: %v", p.name, want, err)
+		}
+		if msg.MoveType != ProtocolError {
+			continue
+		}
+		var text string
+		json.Unmarshal(msg.Data, &text)
+		if !strings.Contains(text, want) {
+			t.Errorf("%s got the error %q, want it to mention %q", p.name, text, want)
+		}
+		return
+	}
+}
+
+// awaitTable reads until the player is sent a description of their table.
+func awaitTable(t *testing.T, p *testPlayer) TableInfo {
+	t.Helper()
+	msg, err := p.await(TableUpdate)
+	if err != nil {
+		t.Fatalf("%s: %v", p.name, err)
+	}
+	var info TableInfo
+	if err := json.Unmarshal(msg.Data, &info); err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+// tables asks for the tables in the lobby.
+func tables(t *testing.T, p *testPlayer) []TableInfo {
+	t.Helper()
+	p.send(ListTables, 0, nil)
+	msg, err := p.await(Tables)
+	if err != nil {
+		t.Fatalf("%s: %v", p.name, err)
+	}
+	var infos []TableInfo
+	if err := json.Unmarshal(msg.Data, &infos); err != nil {
+		t.Fatal(err)
+	}
+	return infos
+}
+
+func TestCreateJoinAndLeaveTables(t *testing.T) {
+	addr := testServer(t, nil)
+	ada, bo, cy := dial(t, addr, "Ada"), dial(t, addr, "Bo"), dial(t, addr, "Cy")
+
+	ada.send(CreateTable, 0, TableData{Name: "friday", MaxPlayers: 3})
+	if info := awaitTable(t, ada); info.MaxPlayers != 3 || !reflect.DeepEqual(info.Players, []string{"Ada"}) {
+		t.Errorf("the new table is %+v, want Ada alone at a table for 3", info)
+	}
+	bo.send(JoinTable, 0, TableData{Name: "friday"})
+	if info := awaitTable(t, bo); !reflect.DeepEqual(info.Players, []string{"Ada", "Bo"}) {
+		t.Errorf("after Bo joined the table is %+v", info)
+	}
+
+	cy.send(CreateTable, 0, TableData{Name: "friday"})
+	expectError(t, cy, "already a table named friday")
+	cy.send(JoinTable, 0, TableData{Name: "saturday"})
+	expectError(t, cy, "no table named saturday")
+	bo.send(CreateTable, 0, TableData{Name: "saturday"})
+	expectError(t, bo, "already at table friday")
+
+	bo.send(LeaveTable, 0, nil)
+	if _, err := bo.await(LeftTable); err != nil {
+		t.Fatal(err)
+	}
+	if infos := tables(t, cy); len(infos) != 1 || !reflect.DeepEqual(infos[0].Players, []string{"Ada"}) {
+		t.Errorf("after Bo left the lobby holds %+v", infos)
+	}
+	ada.send(LeaveTable, 0, nil)
+	if _, err := ada.await(LeftTable); err != nil {
+		t.Fatal(err)
+	}
+	if infos := tables(t, cy); len(infos) != 0 {
+		t.Errorf("the empty table is still open: %+v", infos)
+	}
+	cy.send(LeaveTable, 0, nil)
+	expectError(t, cy, errNoTable.Error())
+}
+
+func TestTableLimits(t *testing.T) {
+	addr := testServer(t, func(s *GameServer) { s.MaxTables = 1 })
+	ada, bo, cy := dial(t, addr, "Ada"), dial(t, addr, "Bo"), dial(t, addr, "Cy")
+
+	for _, seats := range []int{1, 9} {
+		ada.send(CreateTable, 0, TableData{Name: "friday", MaxPlayers: seats})
+		expectError(t, ada, "seat 2 to 8 players")
+	}
+	ada.send(CreateTable, 0, TableData{Name: "friday", MaxPlayers: 2})
+	awaitTable(t, ada)
+	bo.send(CreateTable, 0, TableData{Name: "saturday"})
+	expectError(t, bo, "the lobby is full")
+
+	bo.send(JoinTable, 0, TableData{Name: "friday"})
+	awaitTable(t, bo)
+	cy.send(JoinTable, 0, TableData{Name: "friday"})
+	expectError(t, cy, "cannot join table friday")
+}
+
+func TestMostPlayersReadyStartAfterTheLobbyWait(t *testing.T) {
+	addr := testServer(t, func(s *GameServer) { s.LobbyWait = 50 * time.Millisecond })
+	ada, bo, cy := dial(t, addr, "Ada"), dial(t, addr, "Bo"), dial(t, addr, "Cy")
+	ada.send(CreateTable, 0, TableData{Name: "friday", MaxPlayers: 4})
+	awaitTable(t, ada)
+	for _, p := range []*testPlayer{bo, cy} {
+		p.send(JoinTable, 0, TableData{Name: "friday"})
+		awaitTable(t, p)
+	}
+
+	// Two of three is a majority; Cy never votes
+	ada.send(Ready, 0, nil)
+	bo.send(Ready, 0, nil)
+	bo.send(Ready, 0, nil)
+	expectError(t, bo, "already voted")
+	for _, p := range []*testPlayer{ada, bo, cy} {
+		if _, err := p.await(State); err != nil {
+			t.Fatalf("%s: the game did not start: %v", p.name, err)
+		}
+	}
+}
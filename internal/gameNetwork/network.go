@@ -1,14 +1,15 @@
 package gameNetwork
 
 import (
-	"bufio"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
 	"net"
+	"net/http"
+	"sync"
+	"time"
 
-	"github.com/antongollbo123/chicago-poker/internal/player"
+	"github.com/antongollbo123/chicago-poker/internal/config"
+	"github.com/antongollbo123/chicago-poker/internal/game"
 )
 
 type MessageType string
@@ -16,131 +17,222 @@ type MessageType string
 const (
 	PokerToss    MessageType = "poker_toss"
 	TrickPlay    MessageType = "trick_play"
+	HandClaim    MessageType = "hand_claim"
+	ChicagoCall  MessageType = "chicago_call"
 	GameUpdate   MessageType = "game_update"
 	NextTurn     MessageType = "next_turn"
 	PlayerJoined MessageType = "player_joined"
+
+	// Messages of the JSON protocol, see docs/PROTOCOL.md
+	Hello         MessageType = "hello"
+	Welcome       MessageType = "welcome"
+	Info          MessageType = "info"
+	GameEvent     MessageType = "event"
+	State         MessageType = "state"
+	Prompt        MessageType = "prompt"
+	PlayerAction  MessageType = "action"
+	ProtocolError MessageType = "error"
+
+	// Lobby messages of the JSON protocol
+	ListTables  MessageType = "list_tables"
+	Tables      MessageType = "tables"
+	CreateTable MessageType = "create_table"
+	JoinTable   MessageType = "join_table"
+	LeaveTable  MessageType = "leave_table"
+	Ready       MessageType = "ready"
+	TableUpdate MessageType = "table"
+	LeftTable   MessageType = "left"
+	Seated      MessageType = "seated"
+	Resume      MessageType = "resume"
+	Back        MessageType = "back"
 )
 
+// Message is one line of the JSON protocol. ID ties a prompt to the action
+// answering it and to any error about that action.
 type Message struct {
-	PlayerName string      `json:"player_name"`
+	PlayerName string      `json:"player_name,omitempty"`
 	MoveType   MessageType `json:"move_type"`
-	Data       interface{} `json:"data"`
+	ID         int         `json:"id,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
 }
 
-type Client struct {
-	name   string
-	conn   net.Conn
-	player *player.Player
-}
+// DefaultAddr is where players connect over TCP.
+const DefaultAddr = ":8080"
+
+// DefaultWebAddr is where browsers connect over WebSocket.
+const DefaultWebAddr = ":8081"
 
+// GameServer is the lobby. Clients connect to it, then create, join and
+// leave named tables, each of which runs its own game.
 type GameServer struct {
-	Clients map[*Client]bool
-	Game    *Game
+	Rules       game.Rules // house rules every table plays with
+	Addr        string     // address of the TCP listener used by BuildServer
+	LobbyWait   time.Duration
+	TurnTimeout time.Duration // zero lets players take as long as they like
+	// TurnNotices are the times left on the clock at which a player is
+	// reminded to make their move
+	TurnNotices []time.Duration
+	WebAddr     string // address of the WebSocket endpoint, empty to disable it
+	// WebOrigins are the origins, such as "https://poker.example", of pages
+	// other than the server's own that may open a WebSocket
+	WebOrigins []string
+	MaxTables  int
+	// ReconnectGrace is how long a dropped player has to take their seat back
+	ReconnectGrace time.Duration
+	// TimeoutPolicy makes the move of a player who runs out of time
+	TimeoutPolicy game.Fallback
+	// AwayAfter is how many turns in a row a player may run out of time on
+	// before their moves are made without waiting for them; zero never
+	AwayAfter int
+	// ShutdownWait is how long running hands get to finish on Shutdown
+	ShutdownWait time.Duration
+	// SaveDir is where games still running at shutdown are saved, empty to
+	// not save them
+	SaveDir string
+
+	mu        sync.Mutex // guards clients, tables and the table of each client
+	clients   map[*Client]bool
+	tables    map[string]*Table
+	closing   bool           // Shutdown has been called
+	listeners []net.Listener // TCP listeners being served
+	web       *http.Server   // WebSocket endpoint, nil until it is up
+
+	games sync.WaitGroup // running games
 }
 
-func (s *GameServer) BuildServer() {
-	ln, err := net.Listen("tcp", ":8080")
+func NewGameServer(rules game.Rules) *GameServer {
+	return &GameServer{
+		Rules:          rules,
+		Addr:           DefaultAddr,
+		LobbyWait:      config.DefaultLobbyWait,
+		TurnTimeout:    config.DefaultTurnTimeout,
+		TurnNotices:    []time.Duration{30 * time.Second, 10 * time.Second},
+		TimeoutPolicy:  game.FallbackAuto,
+		AwayAfter:      config.DefaultAwayAfter,
+		WebAddr:        DefaultWebAddr,
+		MaxTables:      config.DefaultMaxTables,
+		ReconnectGrace: config.DefaultReconnectGrace,
+		SaveDir:        config.DefaultSaveDir,
+		clients:        make(map[*Client]bool),
+		tables:         make(map[string]*Table),
+	}
+}
+
+// BuildServer listens on Addr and serves players until Shutdown is called.
+// It returns an error only if the server cannot start.
+func (s *GameServer) BuildServer() error {
+	if err := s.Rules.Validate(); err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+
+	ln, err := net.Listen("tcp", s.Addr)
 	if err != nil {
-		fmt.Println("Error starting the server:", err)
-		return
+		return fmt.Errorf("error starting the server: %w", err)
 	}
-	defer ln.Close()
 
-	fmt.Println("Server is listening on port 8080...")
-	s.Game = NewGame([]*player.Player{})
+	fmt.Printf("Server is listening on %s...\n", ln.Addr())
+	if s.WebAddr != "" {
+		go s.serveWeb()
+	}
+	s.Serve(ln)
+	return nil
+}
+
+// Serve accepts players on a listener until it is closed, which Shutdown
+// does.
+func (s *GameServer) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		ln.Close()
+		return net.ErrClosed
+	}
+	s.listeners = append(s.listeners, ln)
+	s.mu.Unlock()
 
 	for {
 		conn, err := ln.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return err
+		}
 		if err != nil {
 			fmt.Println("Error accepting connection:", err)
 			continue
 		}
 
-		client := &Client{conn: conn}
-		go s.handleConnection(client)
+		go s.handleConnection(newClient(conn))
 	}
 }
 
+// handleConnection greets a client and then reads everything they send,
+// handling lobby commands itself and passing moves on to their table.
 func (s *GameServer) handleConnection(c *Client) {
-	defer func() {
-		c.conn.Close()
-		delete(s.Clients, c)
-		if c.player != nil {
-			fmt.Printf("Player %s disconnected\n", c.player.Name)
-		}
-	}()
+	defer s.disconnect(c)
 
-	s.Clients[c] = true
-	print("connection added", c.conn)
-	playerName := c.setUpUsername()
-	if playerName == "" {
-		fmt.Println("Failed to get player name")
+	playerName, err := c.handshake(s.Rules)
+	if err != nil {
+		fmt.Println("Failed to get player name:", err)
 		return
 	}
-	c.player = &player.Player{Name: playerName}
-	s.Game.AddPlayer(c.player, s)
-	s.broadcastMessage([]byte(fmt.Sprintf("%s has joined the game.", playerName)))
-
-	if len(s.Game.Players) == 2 {
-		s.broadcastMessage([]byte("Starting the game with 2 players!"))
-		go s.Game.StartGame(s)
+	if err := s.register(c); err != nil {
+		c.fail(0, err)
+		return
 	}
-
-	// Keep connection alive by blocking here
-	for {
-		// Check if connection is still alive by trying to read with a timeout
-		// This goroutine stays alive to keep the connection open
+	fmt.Printf("Player %s has entered the lobby.\n", playerName)
+	if !c.json {
+		c.info(fmt.Sprintf("House rules: %s", s.Rules.Summary()))
 	}
-}
-
-func (c *Client) setUpUsername() string {
-
-	if c.conn == nil {
-		fmt.Println("Error: Connection is nil for client.")
-		return ""
+	if s.TurnTimeout > 0 {
+		c.info(fmt.Sprintf("You have %v to make each move.", s.TurnTimeout))
 	}
-	io.WriteString(c.conn, "\n=== Welcome to Chicago Poker ===\nEnter your username: ")
-	scanner := bufio.NewScanner(c.conn)
-	scanner.Scan()
-	c.name = scanner.Text()
-	io.WriteString(c.conn, fmt.Sprintf("Welcome, %s! Waiting for other players...\n", c.name))
-	return c.name
-}
-
-func (s *GameServer) broadcastMessage(msg []byte) {
-	// Ensure message ends with newline
-	if len(msg) > 0 && msg[len(msg)-1] != '\n' {
-		msg = append(msg, '\n')
+	s.listTables(c)
+	if !c.json {
+		c.info(lobbyHelp)
 	}
-	for c := range s.Clients {
-		_, err := c.conn.Write(msg)
+
+	for {
+		line, err := c.readLine()
 		if err != nil {
-			log.Printf("Error sending message to client: %v", err)
+			return
 		}
+		s.handleInput(c, line)
 	}
 }
 
-func (s *GameServer) sendMessageToPlayer(playerName string, msg Message) error {
-	for client := range s.Clients { // Iterate over all connected clients
-		if client.player.Name == playerName { // Match by player name
-			jsonMsg, err := json.Marshal(msg)
-			if err != nil {
-				return err
-			}
-			_, err = client.conn.Write(jsonMsg) // Send the message
-			return err
+// register adds a client to the lobby. Names are unique across the server.
+func (s *GameServer) register(c *Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return errShuttingDown
+	}
+	for other := range s.clients {
+		if other.name == c.name {
+			return fmt.Errorf("the name %q is taken", c.name)
 		}
 	}
-	return fmt.Errorf("Client for player %s not found", playerName)
+	s.clients[c] = true
+	return nil
 }
 
-func (s *GameServer) getPlayerConnection(playerName string) net.Conn {
-	for client := range s.Clients {
-		if client.name == playerName {
-			return client.conn
+// disconnect removes a client from the lobby. A seat in a running game is
+// kept for them to resume.
+func (s *GameServer) disconnect(c *Client) {
+	s.mu.Lock()
+	delete(s.clients, c)
+	if t := c.table; t != nil {
+		c.table = nil
+		if t.drop(c) {
+			delete(s.tables, t.Name)
 		}
 	}
-	return nil
+	s.mu.Unlock()
+	// Only now, with the seat marked as dropped, may the game see the end of
+	// their input
+	close(c.input)
+
+	c.close()
+	if c.name != "" {
+		fmt.Printf("Player %s disconnected\n", c.name)
+	}
 }
-
-// TODO: Add format message function
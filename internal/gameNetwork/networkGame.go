@@ -1,403 +1,330 @@
 package gameNetwork
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"log"
-	"reflect"
-	"sort"
 	"strings"
+	"time"
 
-	"github.com/antongollbo123/chicago-poker/internal/deck"
 	"github.com/antongollbo123/chicago-poker/internal/game"
-	"github.com/antongollbo123/chicago-poker/internal/player"
 	"github.com/antongollbo123/chicago-poker/pkg/cards"
 )
 
-type Stage string
-type Chicago bool
+const maxRetries = 3
 
-const (
-	Poker Stage = "Poker"
-	Trick Stage = "Trick"
-)
-
-const (
-	TrickWin int = 3
-)
-
-type Game struct {
-	Deck      *deck.Deck
-	Players   []*player.Player
-	Round     int
-	Stage     Stage
-	leadIndex int
-}
-
-func NewGame(players []*player.Player) *Game {
-	game := Game{
-		Players: players,
-		Round:   0,
-		Stage:   Poker,
-	}
+// run drives the rules engine until the game is over, prompting each player
+// in turn over their connection and broadcasting what happens. The table is
+// closed when it returns.
+func (t *Table) run() {
+	defer t.server.games.Done()
+	defer t.server.closeTable(t)
 
-	deck := deck.NewDeck()
-	deck.Shuffle()
-	game.Deck = deck
-	return &game
-}
-
-func (g *Game) Deal() {
-	for _, player := range g.Players {
-		cards := g.Deck.DrawMultiple(5)
-		player.Hand = cards
-	}
-}
-
-func (g *Game) TossCards(playerIndex int, indicesToRemove []int) {
-	if playerIndex < 0 || playerIndex >= len(g.Players) {
-		// TODO: Handle invalid player index
+	g := t.game
+	t.mu.Lock()
+	events, err := g.Start()
+	t.mu.Unlock()
+	if err != nil {
+		fmt.Printf("Error starting the game at table %s: %v\n", t.Name, err)
+		t.broadcastMessage(fmt.Sprintf("The game could not start: %v", err))
 		return
 	}
-
-	// Sort indicesToRemove in descending order to safely remove cards from hand slice
-	sort.Sort(sort.Reverse(sort.IntSlice(indicesToRemove)))
-
-	// Remove cards from player's hand based on indicesToRemove
-	for _, idx := range indicesToRemove {
-		if idx >= 0 && idx < len(g.Players[playerIndex].Hand) {
-			g.Players[playerIndex].Hand = append(g.Players[playerIndex].Hand[:idx], g.Players[playerIndex].Hand[idx+1:]...)
+	t.broadcastMessage(fmt.Sprintf("Starting the game with %d players!", len(g.Players)))
+	fmt.Printf("Game at table %s started\n", t.Name)
+	t.broadcastEvents(events)
+
+	for g.Stage != game.Over {
+		if t.halted() {
+			t.save()
+			return
 		}
-	}
-}
-
-func (g *Game) getHighScore() int {
-	highScore := 0
-	for _, player := range g.Players {
-		if player.Score > highScore {
-			highScore = player.Score
+		if t.abandoned() {
+			fmt.Printf("Everyone left table %s, abandoning its game\n", t.Name)
+			return
 		}
-	}
-	fmt.Println("high score is: ", highScore)
-	return highScore
-}
-
-func (g *Game) StartGame(server *GameServer) {
-	g.Deal()
-	for g.getHighScore() < 50 {
-		switch g.Stage {
-		case Poker:
-			g.PokerRound(server)
-		case Trick:
-			g.TrickRound(server)
+		if g.Stage == game.Trick && g.CurrentTrick()[g.Lead()] == (cards.Card{}) {
+			t.broadcastMessage(fmt.Sprintf("Starting trick %d", g.TrickNumber()+1))
+		}
+		events := t.playTurn()
+		t.broadcastEvents(events)
+		if t.isFinishing() && handOver(events) {
+			t.stop()
 		}
-
 	}
 
-}
-
-func (g *Game) AddPlayer(player *player.Player, server *GameServer) {
-	if g == nil {
-		fmt.Println("Error: Game instance is nil.")
+	// Keep the full record in the server log so any game can be replayed
+	record, err := json.Marshal(g.Record())
+	if err != nil {
+		fmt.Printf("Error marshaling game record: %v\n", err)
 		return
 	}
-	print("PLAYER NAME INSIDE: ", player.Name)
-	g.Players = append(g.Players, player)
-	fmt.Printf("Player %s has been added to the game.\n", player.Name)
+	fmt.Printf("Game record of table %s: %s\n", t.Name, record)
+}
 
-	// Optionally notify the server or other players about the new player
-	msg := Message{
-		PlayerName: player.Name,
-		MoveType:   PlayerJoined,
-		Data:       fmt.Sprintf("%s has joined the game!", player.Name),
+// abandoned reports whether every player has left the table for good.
+func (t *Table) abandoned() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for seat, c := range t.seats {
+		if until, away := t.away[seat]; c != nil || away && time.Now().Before(until) {
+			return false
+		}
 	}
-	g.notifyServer(server, msg)
+	return true
 }
 
-func (g *Game) PokerRound(server *GameServer) {
-	fmt.Println("Starting Poker Round: ", g.Round+1)
-
-	for _, player := range g.Players {
-		// Send the player's hand
+// playTurn asks the player whose turn it is for a move until the engine
+// accepts one. After maxRetries invalid moves, or if the player runs out of
+// time, is not connected or is marked away, TimeoutPolicy moves for them.
+// Nobody moves if the game is halted in the meantime.
+func (t *Table) playTurn() []game.Event {
+	g := t.game
+	playerIndex := g.Turn()
+	currentPlayer := g.Players[playerIndex]
+
+	for retry := 0; retry < maxRetries; retry++ {
+		if t.isIdle(playerIndex) {
+			break
+		}
+		c := t.client(playerIndex)
+		if c == nil {
+			if c = t.awaitReturn(playerIndex); c == nil {
+				break
+			}
+		}
 		handMsg := Message{
-			PlayerName: player.Name,
+			PlayerName: currentPlayer.Name,
 			MoveType:   GameUpdate,
-			Data:       player.Hand,
+			Data:       currentPlayer.Hand,
 		}
-		g.notifyServer(server, handMsg)
+		notifyPlayer(c, handMsg)
 
-		// Ask for the player's move
-		promptMsg := Message{
-			PlayerName: player.Name,
-			MoveType:   PokerToss,
-			Data:       "Enter the indices of the cards you want to toss, separated by spaces: ",
+		moveType := PokerToss
+		switch g.Stage {
+		case game.Showdown:
+			moveType = HandClaim
+		case game.Chicago:
+			moveType = ChicagoCall
+		case game.Trick:
+			moveType = TrickPlay
 		}
-		g.notifyServer(server, promptMsg)
-
-	}
-	bestPlayerIndex, bestHandEvaluation := g.EvaluateHands()
-	formattedMsg := fmt.Sprintf("Player %s wins the round with a %v of %v and gets %d points\n",
-		g.Players[bestPlayerIndex].Name,
-		bestHandEvaluation.Rank,
-		bestHandEvaluation.ScoreCards,
-		bestHandEvaluation.Score)
-	msgBytes := []byte(formattedMsg)
-	server.broadcastMessage(msgBytes)
-	g.Round++
-
-	if g.Round > 2 {
-		g.Stage = Trick
-	}
-}
-
-func (g *Game) TrickRound(server *GameServer) {
-	server.broadcastMessage([]byte("TRICK ROUND!"))
-	leadIndex := g.leadIndex
-
-	for trick := 0; trick < 5; trick++ {
-		var leadCard cards.Card
-		playedCards := make([]cards.Card, len(g.Players))
-		indicesToRemove := make([]int, len(g.Players))
-
-		server.broadcastMessage([]byte(fmt.Sprintf("Starting trick %d\n", trick+1)))
-
-		for i := 0; i < len(g.Players); i++ {
-			playerIndex := (leadIndex + i) % len(g.Players)
-			currentPlayer := g.Players[playerIndex]
-
-			// Notify player of their hand
-			handMsg := Message{
-				PlayerName: currentPlayer.Name,
-				MoveType:   GameUpdate,
-				Data:       currentPlayer.Hand,
-			}
-			g.notifyServer(server, handMsg)
-
-			// Ask for the player's move with retry limit
-			var cardIndex []int
-			maxRetries := 3
-			for retry := 0; retry < maxRetries; retry++ {
-				promptMsg := Message{
-					PlayerName: currentPlayer.Name,
-					MoveType:   TrickPlay,
-					Data:       "Enter the index of the card you want to play: ",
-				}
-				cardIndex = g.notifyServer(server, promptMsg)
-
-				if len(cardIndex) != 1 {
-					fmt.Printf("Invalid card index from %s. Retry %d/%d\n", currentPlayer.Name, retry+1, maxRetries)
-					continue
-				}
-
-				// Validate card index is in range
-				if cardIndex[0] < 0 || cardIndex[0] >= len(currentPlayer.Hand) {
-					fmt.Printf("Card index out of range from %s. Retry %d/%d\n", currentPlayer.Name, retry+1, maxRetries)
-					continue
-				}
-
-				playedCard := currentPlayer.Hand[cardIndex[0]]
-
-				// Validate trick move rules
-				if i == 0 {
-					leadCard = playedCard // Set the lead card for the trick
-					break
-				} else if !isValidTrickMove(currentPlayer, playedCard, leadCard) {
-					fmt.Printf("Invalid move from %s. You must follow the suit if possible. Retry %d/%d\n", currentPlayer.Name, retry+1, maxRetries)
-					continue
-				}
-
+		id := t.nextPromptID()
+		action, err := t.promptPlayer(c, playerIndex, moveType, id)
+		if err == errTimeout {
+			t.missTurn(c, playerIndex)
+			break
+		}
+		if err != nil {
+			if t.client(playerIndex) == c {
 				break
 			}
-
-			// If still invalid after retries, play the first valid card automatically
-			if len(cardIndex) != 1 || cardIndex[0] < 0 || cardIndex[0] >= len(currentPlayer.Hand) {
-				fmt.Printf("Player %s failed to provide valid input. Auto-playing first card.\n", currentPlayer.Name)
-				cardIndex = []int{0}
-			}
-
-			playedCard := currentPlayer.Hand[cardIndex[0]]
-			playedCards[playerIndex] = playedCard
-			indicesToRemove[playerIndex] = cardIndex[0]
-
-			fmt.Printf("Player %s played %v\n", currentPlayer.Name, playedCard)
+			// They got up or dropped while we waited; wait for them instead
+			retry--
+			continue
 		}
+		t.answered(playerIndex)
 
-		winnerIndex := findWinner(playedCards, leadIndex)
-		fmt.Printf("Player %s wins the trick with %v\n", g.Players[winnerIndex].Name, playedCards[winnerIndex])
-
-		// Remove played cards
-		for playerIdx, cardIdx := range indicesToRemove {
-			if cardIdx >= 0 {
-				g.TossCards(playerIdx, []int{cardIdx})
-			}
+		events, err := t.apply(action)
+		if err == nil {
+			return events
+		}
+		fmt.Printf("Invalid move from %s: %v. Retry %d/%d\n", currentPlayer.Name, err, retry+1, maxRetries)
+		if c.json {
+			c.fail(id, err)
+		} else {
+			notifyPlayer(c, Message{
+				PlayerName: currentPlayer.Name,
+				MoveType:   GameUpdate,
+				Data:       fmt.Sprintf("Invalid move: %v. Try again.", err),
+			})
 		}
-
-		leadIndex = winnerIndex // Update lead index for the next trick
 	}
 
-	// Award points to the player who wins the final trick
-	g.Players[leadIndex].Score += TrickWin
-	g.Round++
-	fmt.Printf("Player %s wins the trick round and gets %d points\n", g.Players[leadIndex].Name, TrickWin)
+	if t.halted() {
+		return nil
+	}
+	fmt.Printf("Player %s failed to provide valid input. Auto-playing.\n", currentPlayer.Name)
+	events, err := t.apply(t.TimeoutPolicy.Action(g, playerIndex))
+	if err != nil {
+		fmt.Printf("Error auto-playing for %s: %v\n", currentPlayer.Name, err)
+	}
+	return events
+}
 
-	g.Stage = Poker // Switch back to Poker round
-	g.Deal()
+// apply plays an action, holding the table's lock so that others can look
+// at the game while it runs.
+func (t *Table) apply(action game.Action) ([]game.Event, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.game.Apply(action)
 }
 
-// Check if a player has a card of a given suit
-func hasSuit(hand []cards.Card, suit cards.Suit) bool {
-	for _, card := range hand {
-		if card.Suit == suit {
-			return true
+// textAction turns a line typed by a text client into the action it stands
+// for at the given kind of prompt.
+func textAction(moveType MessageType, playerIndex int, reply string) game.Action {
+	indices := game.ParseInput(reply)
+	switch {
+	case moveType == HandClaim:
+		return claimAction(playerIndex, reply)
+	case moveType == ChicagoCall:
+		if game.ParseYes(reply) {
+			return game.Declare(playerIndex)
 		}
+		return game.Pass(playerIndex)
+	case moveType == TrickPlay:
+		return game.Action{Type: game.ActionPlay, Player: playerIndex, Cards: indices}
+	case len(indices) == 0:
+		return game.StandPat(playerIndex)
 	}
-	return false
+	return game.Toss(playerIndex, indices...)
 }
 
-// Find the winner of the current trick
-func findWinner(playedCards []cards.Card, leadIndex int) int {
-	leadSuit := playedCards[leadIndex].Suit
-	highestCard := playedCards[leadIndex]
-	highestIndex := leadIndex
-
-	for i, card := range playedCards {
-		if card.Suit == leadSuit && card.Rank > highestCard.Rank {
-			highestCard = card
-			highestIndex = i
-		}
+// claimAction claims the named hand, or passes on an empty reply. A name that
+// is not a hand makes an invalid claim for the engine to reject.
+func claimAction(playerIndex int, reply string) game.Action {
+	if reply == "" {
+		return game.Pass(playerIndex)
 	}
-
-	return highestIndex
+	rank, _ := game.ParseHandRank(reply)
+	return game.Claim(playerIndex, rank)
 }
 
-func (g *Game) EvaluateHands() (int, game.HandEvaluation) {
-	bestScore := -1
-	bestPlayerIndex := -1
-	var bestHandEvaluation game.HandEvaluation
-	for i := 0; i < len(g.Players)-1; i++ {
-		for j := i + 1; j < len(g.Players); j++ {
-			winningHand, winningHandEvaluation := game.EvaluateTwoHands(g.Players[i].Hand, g.Players[j].Hand)
-			if winningHandEvaluation.Score > bestScore {
-				bestScore = winningHandEvaluation.Score
-				bestHandEvaluation = winningHandEvaluation
-				if reflect.DeepEqual(winningHand, g.Players[i].Hand) {
-					bestPlayerIndex = i
-				} else if reflect.DeepEqual(winningHand, g.Players[j].Hand) {
-					bestPlayerIndex = j
-				}
+// broadcastEvents tells everyone at the table what happened. Text clients
+// get a line per event; JSON clients get the events, without other players'
+// private cards, followed by their view of the game.
+func (t *Table) broadcastEvents(events []game.Event) {
+	for seat, c := range t.members() {
+		if c == nil {
+			continue
+		}
+		if !c.json {
+			for _, e := range events {
+				c.info(t.game.Describe(e))
 			}
+			continue
 		}
+		for _, e := range events {
+			seen := e
+			if e.Player != seat {
+				seen = e.Redact()
+			}
+			c.send(Message{MoveType: GameEvent, Data: EventData{Event: seen, Text: t.game.Describe(e)}})
+		}
+		c.send(Message{PlayerName: c.name, MoveType: State, Data: t.game.Snapshot(seat)})
 	}
-
-	if bestPlayerIndex != -1 {
-		g.Players[bestPlayerIndex].Score += bestScore
-		return bestPlayerIndex, bestHandEvaluation
-	}
-	return 0, game.HandEvaluation{}
 }
 
-func describe(i interface{}) {
-	fmt.Printf("(%v, %T)\n", i, i)
+// nextPromptID numbers the prompts sent at the table.
+func (t *Table) nextPromptID() int {
+	t.promptID++
+	return t.promptID
 }
 
-func (g *Game) processMove(playerName string, moveType MessageType, data interface{}) error {
-	log.Printf("Processing move: Player: %s, Type: %s, Data: %v\n", playerName, moveType, data)
-
-	fmt.Print(moveType == "poker_toss")
-	fmt.Print(moveType == PokerToss)
-	describe(data)
-
-	intIndices, ok := data.([]int)
-	fmt.Print(intIndices)
-	if !ok {
-		return fmt.Errorf("invalid data format")
+// promptPlayer asks a player for their move and waits for their reply. It
+// fails with errTimeout if they do not answer in time, and with another
+// error if they leave.
+func (t *Table) promptPlayer(c *Client, playerIndex int, moveType MessageType, id int) (game.Action, error) {
+	g := t.game
+	var timeout <-chan time.Time
+	if t.TurnTimeout > 0 {
+		expired, stop := countdown(c, t.TurnTimeout, t.TurnNotices)
+		defer stop()
+		timeout = expired
 	}
 
-	playerIndex := g.getPlayerIndex(playerName)
-	if playerIndex == -1 {
-		return fmt.Errorf("player not found")
-	}
-
-	if moveType == "poker_toss" {
-		g.TossCards(playerIndex, intIndices)
-		newCards := g.Deck.DrawMultiple(len(intIndices))
-		g.Players[playerIndex].Hand = append(g.Players[playerIndex].Hand, newCards...)
-		log.Printf("Player %s has new hand: %v\n", playerName, g.Players[playerIndex].Hand)
-	}
-	return nil
-}
-
-func (g *Game) processTrickMove(moveType MessageType, data interface{}) []int {
-	if moveType == "trick_play" {
-		intIndices, _ := data.([]int)
-		fmt.Println("intindices in processTrickMove", intIndices)
-		return intIndices
+	if c.json {
+		prompt := PromptData{Kind: moveType, Seat: playerIndex, Timeout: int(t.TurnTimeout / time.Second)}
+		switch moveType {
+		case HandClaim:
+			prompt.BestClaim = g.BestClaim()
+		case TrickPlay:
+			prompt.LegalPlays = g.LegalPlays(playerIndex)
+		}
+		c.send(Message{PlayerName: c.name, MoveType: Prompt, ID: id, Data: prompt})
+		action, err := c.readAction(id, timeout, t.vacated, t.halt)
+		for err == errVacated && t.client(playerIndex) == c {
+			// Someone else got up; keep waiting for this player
+			action, err = c.readAction(id, timeout, t.vacated, t.halt)
+		}
+		if err != nil {
+			if err != errHalted {
+				fmt.Printf("Error reading from player %s: %v\n", c.name, err)
+			}
+			return game.Action{}, err
+		}
+		action.Player = playerIndex
+		return action, nil
 	}
-	return nil
-}
 
-func isValidTrickMove(player *player.Player, playedCard cards.Card, leadCard cards.Card) bool {
-	// Check if the player has a card of the lead suit
-	if hasSuit(player.Hand, leadCard.Suit) {
-		// If the played card matches the lead suit, it's valid
-		return playedCard.Suit == leadCard.Suit
+	prompt := "\nEnter the indices of cards to toss (space-separated, e.g., '0 2 4'), or press Enter to stand pat: "
+	switch moveType {
+	case HandClaim:
+		prompt = "\nAnnounce your hand (e.g., 'pair', 'two pair', 'flush'), or press Enter to pass: "
+		if best := g.BestClaim(); best != game.HighCard {
+			prompt = fmt.Sprintf("\nBest claim so far: %v. Announce a hand at least as good, or press Enter to pass: ", best)
+		}
+	case ChicagoCall:
+		prompt = "\nDeclare Chicago and claim all five tricks? (y/N): "
+	case TrickPlay:
+		prompt = "\nEnter card index to play (0-4): "
 	}
-	// If the player does not have the lead suit, any card is valid
-	return true
-}
-
-func (g *Game) notifyServer(server *GameServer, msg Message) []int {
-
-	fmt.Println("I AM IN NOTIFYSERVER, MOVE TYPE: ", msg.MoveType)
-	playerConn := server.getPlayerConnection(msg.PlayerName)
-
-	if playerConn == nil {
-		fmt.Printf("ERROR: No connection found for player %s - they may have disconnected\n", msg.PlayerName)
-		return nil
+	c.drain()
+	c.write(prompt)
+	content, err := c.nextLine(timeout, t.vacated, t.halt)
+	for err == errVacated && t.client(playerIndex) == c {
+		content, err = c.nextLine(timeout, t.vacated, t.halt)
 	}
-
-	if msg.MoveType == "poker_toss" {
-		reader := bufio.NewReader(playerConn)
-		playerConn.Write([]byte("\nEnter the indices of cards to toss (space-separated, e.g., '0 2 4'): "))
-		content, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Printf("Error reading from player %s: %v\n", msg.PlayerName, err)
-			return nil
+	if err != nil {
+		if err != errHalted {
+			fmt.Printf("Error reading from player %s: %v\n", c.name, err)
 		}
-		content = strings.TrimSpace(content)
-		msg.MoveType = PokerToss // TODO: Redundant ? --> Remove?
-		msg.Data = game.ParseInput(content)
-		fmt.Println(msg.Data)
-		g.processMove(msg.PlayerName, msg.MoveType, msg.Data)
-		return game.ParseInput(content)
+		return game.Action{}, err
 	}
+	return textAction(moveType, playerIndex, strings.TrimSpace(content)), nil
+}
 
-	if msg.MoveType == "trick_play" {
-		reader := bufio.NewReader(playerConn)
-		playerConn.Write([]byte("\nEnter card index to play (0-4): "))
-		content, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Printf("Error reading from player %s: %v\n", msg.PlayerName, err)
-			return nil
-		}
-		content = strings.TrimSpace(content)
-
-		parsedCardIndex := game.ParseInput(content)
-		if len(parsedCardIndex) != 1 {
-			fmt.Println("Invalid input. Please enter a single valid card index.")
-			return nil
+// countdown reminds a player of the time left at each of the notices as the
+// end of their turn nears, and fires the returned channel once limit has
+// passed. Calling stop ends it early.
+func countdown(c *Client, limit time.Duration, notices []time.Duration) (expired <-chan time.Time, stop func()) {
+	fire := make(chan time.Time, 1)
+	done := make(chan struct{})
+	deadline := time.Now().Add(limit)
+	go func() {
+		for _, left := range notices {
+			if left >= limit {
+				continue
+			}
+			if !waitUntil(deadline.Add(-left), done) {
+				return
+			}
+			c.info(fmt.Sprintf("\n%v left to make your move.", left))
 		}
-
-		if parsedCardIndex[0] < 0 || parsedCardIndex[0] >= len(g.Players[g.getPlayerIndex(msg.PlayerName)].Hand) {
-			fmt.Println("Index out of range. Try again.")
-			return nil
+		if waitUntil(deadline, done) {
+			fire <- deadline
 		}
+	}()
+	return fire, func() { close(done) }
+}
 
-		msg.Data = parsedCardIndex
-		return parsedCardIndex
+// waitUntil sleeps until the given time and reports true, or reports false
+// as soon as done is closed.
+func waitUntil(when time.Time, done <-chan struct{}) bool {
+	timer := time.NewTimer(time.Until(when))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-done:
+		return false
 	}
+}
 
+// notifyPlayer shows a text client their hand or a message. JSON clients
+// already find their hand in the state messages.
+func notifyPlayer(c *Client, msg Message) {
+	if c.json {
+		return
+	}
 	// Format message based on type
 	var formattedMsg string
 	if msg.MoveType == GameUpdate {
@@ -418,25 +345,13 @@ func (g *Game) notifyServer(server *GameServer, msg Message) []int {
 		jsonData, err := json.Marshal(msg)
 		if err != nil {
 			fmt.Printf("Error marshaling message for player %s: %v\n", msg.PlayerName, err)
-			return nil
+			return
 		}
 		formattedMsg = fmt.Sprintf("\n%s\n", string(jsonData))
 	}
 
-	// Send the formatted message
-	_, err := playerConn.Write([]byte(formattedMsg))
+	err := c.write(formattedMsg)
 	if err != nil {
 		fmt.Printf("Failed to send message to player %s: %v\n", msg.PlayerName, err)
-		return nil
-	}
-	return nil
-}
-
-func (g *Game) getPlayerIndex(playerName string) int {
-	for i, p := range g.Players {
-		if p.Name == playerName {
-			return i
-		}
 	}
-	return -1
 }
@@ -0,0 +1,496 @@
+package gameNetwork
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antongollbo123/chicago-poker/internal/config"
+	"github.com/antongollbo123/chicago-poker/internal/game"
+)
+
+// testServer starts a server on a free port and returns its address.
+func testServer(t *testing.T, configure func(*GameServer)) string {
+	t.Helper()
+	_, addr := startServer(t, configure)
+	return addr
+}
+
+// startServer is testServer for tests that need the server itself.
+func startServer(t *testing.T, configure func(*GameServer)) (*GameServer, string) {
+	t.Helper()
+	s := NewGameServer(game.DefaultRules())
+	s.SaveDir = t.TempDir()
+	s.WebAddr = ""
+	if configure != nil {
+		configure(s)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(ln)
+	// Stop every game, so that none outlives its test
+	t.Cleanup(s.Shutdown)
+	return s, ln.Addr().String()
+}
+
+// testPlayer is a client speaking the JSON protocol. Its methods return
+// errors rather than failing the test so that players can run in their own
+// goroutines.
+type testPlayer struct {
+	name    string
+	conn    net.Conn
+	scanner *bufio.Scanner
+	token   string // resume token of the player's seat
+	stage   string // stage of the last state seen
+}
+
+func dial(t *testing.T, addr, name string) *testPlayer {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	p := &testPlayer{name: name, conn: conn, scanner: bufio.NewScanner(conn)}
+	p.send(Hello, 0, HelloData{Version: ProtocolVersion, Name: name})
+	if _, err := p.await(Welcome); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return p
+}
+
+func (p *testPlayer) send(moveType MessageType, id int, data interface{}) error {
+	line, err := json.Marshal(Message{MoveType: moveType, ID: id, Data: data})
+	if err != nil {
+		return err
+	}
+	_, err = p.conn.Write(append(line, '\n'))
+	return err
+}
+
+// next reads the player's next message, skipping the text banner.
+func (p *testPlayer) next() (incoming, error) {
+	p.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for p.scanner.Scan() {
+		line := p.scanner.Text()
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var msg incoming
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			return incoming{}, fmt.Errorf("%s: %v", line, err)
+		}
+		switch msg.MoveType {
+		case Seated:
+			var seat SeatData
+			json.Unmarshal(msg.Data, &seat)
+			p.token = seat.Token
+		case State:
+			var state struct {
+				Stage string `json:"stage"`
+			}
+			json.Unmarshal(msg.Data, &state)
+			p.stage = state.Stage
+		}
+		return msg, nil
+	}
+	if err := p.scanner.Err(); err != nil {
+		return incoming{}, err
+	}
+	return incoming{}, errors.New("connection closed")
+}
+
+// await reads messages until one of the given type arrives.
+func (p *testPlayer) await(moveType MessageType) (incoming, error) {
+	for {
+		msg, err := p.next()
+		if err != nil {
+			return incoming{}, fmt.Errorf("waiting for %s: %v", moveType, err)
+		}
+		if msg.MoveType == moveType {
+			return msg, nil
+		}
+		if msg.MoveType == ProtocolError {
+			return incoming{}, fmt.Errorf("waiting for %s: error %s", moveType, msg.Data)
+		}
+	}
+}
+
+// play answers prompts until the player is back in the lobby, or until it
+// has answered the given number of them.
+func (p *testPlayer) play(prompts int) error {
+	for answered := 0; answered != prompts; {
+		msg, err := p.next()
+		if err != nil {
+			return fmt.Errorf("%s: %v", p.name, err)
+		}
+		switch msg.MoveType {
+		case LeftTable:
+			return nil
+		case ProtocolError:
+			return fmt.Errorf("%s: error %s", p.name, msg.Data)
+		case Prompt:
+			var prompt PromptData
+			if err := json.Unmarshal(msg.Data, &prompt); err != nil {
+				return err
+			}
+			action := game.StandPat(prompt.Seat)
+			switch prompt.Kind {
+			case HandClaim, ChicagoCall:
+				action = game.Pass(prompt.Seat)
+			case TrickPlay:
+				action = game.Play(prompt.Seat, prompt.LegalPlays[0])
+			}
+			if err := p.send(PlayerAction, msg.ID, action); err != nil {
+				return err
+			}
+			answered++
+		}
+	}
+	return nil
+}
+
+// sitDown opens a table for two and seats both players, which starts the
+// game.
+func sitDown(t *testing.T, host, guest *testPlayer) {
+	t.Helper()
+	host.send(CreateTable, 0, TableData{Name: "friday", MaxPlayers: 2})
+	if _, err := host.await(TableUpdate); err != nil {
+		t.Fatalf("%s: %v", host.name, err)
+	}
+	guest.send(JoinTable, 0, TableData{Name: "friday"})
+}
+
+// playInBackground plays to the end of the game and reports how it went.
+func playInBackground(p *testPlayer) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- p.play(-1) }()
+	return done
+}
+
+func TestNewServerUsesTheConfigDefaults(t *testing.T) {
+	cfg, s := config.Default(), NewGameServer(game.DefaultRules())
+	server := config.Config{
+		Rules:          s.Rules,
+		TurnTimeout:    s.TurnTimeout,
+		TimeoutPolicy:  s.TimeoutPolicy,
+		AwayAfter:      s.AwayAfter,
+		LobbyWait:      s.LobbyWait,
+		MaxTables:      s.MaxTables,
+		ReconnectGrace: s.ReconnectGrace,
+		ShutdownWait:   s.ShutdownWait,
+		SaveDir:        s.SaveDir,
+	}
+	if !reflect.DeepEqual(cfg, server) {
+		t.Errorf("config.Default() = %+v, but a new server uses %+v", cfg, server)
+	}
+}
+
+func TestTwoPlayersFinishAGame(t *testing.T) {
+	addr := testServer(t, nil)
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+
+	adaDone := playInBackground(ada)
+	if err := bo.play(-1); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-adaDone; err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*testPlayer{ada, bo} {
+		if p.stage != "Over" {
+			t.Errorf("%s last saw the game in stage %q, want Over", p.name, p.stage)
+		}
+	}
+}
+
+func TestDroppedPlayerResumesTheirSeat(t *testing.T) {
+	addr := testServer(t, nil)
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+
+	adaDone := playInBackground(ada)
+	if err := bo.play(5); err != nil {
+		t.Fatal(err)
+	}
+	bo.conn.Close()
+
+	back := dial(t, addr, "Bo again")
+	back.send(Resume, 0, SeatData{Token: bo.token})
+	if _, err := back.await(State); err != nil {
+		t.Fatal(err)
+	}
+	if err := back.play(-1); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-adaDone; err != nil {
+		t.Fatal(err)
+	}
+	if back.stage != "Over" {
+		t.Errorf("the resumed player last saw stage %q, want Over", back.stage)
+	}
+}
+
+func TestAbandonedSeatIsPlayedFor(t *testing.T) {
+	addr := testServer(t, func(s *GameServer) { s.ReconnectGrace = 10 * time.Millisecond })
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+
+	// Leaving before the game starts would give up the seat instead
+	if _, err := bo.await(State); err != nil {
+		t.Fatal(err)
+	}
+	bo.conn.Close()
+	if err := ada.play(-1); err != nil {
+		t.Fatal(err)
+	}
+	if ada.stage != "Over" {
+		t.Errorf("the remaining player last saw stage %q, want Over", ada.stage)
+	}
+}
+
+func TestBadNamesAreRefused(t *testing.T) {
+	addr := testServer(t, nil)
+	for _, name := range []string{"", strings.Repeat("a", maxPlayerName+1), "Ada\x1b[2J"} {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := &testPlayer{name: name, conn: conn, scanner: bufio.NewScanner(conn)}
+		p.send(Hello, 0, HelloData{Version: ProtocolVersion, Name: name})
+		if msg, err := p.next(); err != nil || msg.MoveType != ProtocolError {
+			t.Errorf("hello as %q got %+v, %v, want an error", name, msg, err)
+		}
+		conn.Close()
+	}
+	// Names of the longest length are fine
+	dial(t, addr, strings.Repeat("ö", maxPlayerName))
+}
+
+func TestIdlePlayersAreMovedForAndMarkedAway(t *testing.T) {
+	addr := testServer(t, func(s *GameServer) {
+		s.TurnTimeout = 250 * time.Millisecond
+		s.TurnNotices = []time.Duration{100 * time.Millisecond}
+		s.AwayAfter = 2
+	})
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+	playInBackground(ada)
+
+	// Bo never answers: they are reminded, moved for, and away after two turns
+	seat, reminded, timedOut, movedFor := -1, false, false, false
+	for {
+		msg, err := bo.next()
+		if err != nil {
+			t.Fatalf("reminded %v, timed out %v, moved for %v: %v", reminded, timedOut, movedFor, err)
+		}
+		var text string
+		switch msg.MoveType {
+		case Prompt:
+			var prompt PromptData
+			json.Unmarshal(msg.Data, &prompt)
+			seat = prompt.Seat
+		case Info:
+			json.Unmarshal(msg.Data, &text)
+			reminded = reminded || strings.Contains(text, "left to make your move")
+			timedOut = timedOut || strings.Contains(text, "Time is up")
+		case GameEvent:
+			var e EventData
+			json.Unmarshal(msg.Data, &e)
+			movedFor = movedFor || timedOut && e.Event.Player == seat
+		case TableUpdate:
+			var info TableInfo
+			json.Unmarshal(msg.Data, &info)
+			if reflect.DeepEqual(info.Away, []string{"Bo"}) {
+				if !reminded || !timedOut || !movedFor {
+					t.Errorf("marked away before being reminded (%v), timed out (%v) and moved for (%v)", reminded, timedOut, movedFor)
+				}
+				goto away
+			}
+		}
+	}
+away:
+	bo.send(Back, 0, nil)
+	for {
+		msg, err := bo.await(TableUpdate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var info TableInfo
+		json.Unmarshal(msg.Data, &info)
+		if len(info.Away) == 0 {
+			break
+		}
+	}
+	bo.send(Back, 0, nil)
+	expectError(t, bo, "not marked away")
+}
+
+func TestSlowClientIsHungUpOn(t *testing.T) {
+	server, peer := net.Pipe()
+	defer peer.Close()
+	c := newClient(server)
+
+	done := make(chan error, 1)
+	go func() {
+		// Nobody reads from the pipe, so the writer is stuck on its first write
+		for i := 0; i < outputBuffer+2; i++ {
+			if err := c.write("hello\n"); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		if err != errSlowClient {
+			t.Errorf("write() error = %v, want %v", err, errSlowClient)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("write() blocked on a client that does not read")
+	}
+	if err := c.write("more\n"); err != net.ErrClosed {
+		t.Errorf("write() after hanging up error = %v, want %v", err, net.ErrClosed)
+	}
+}
+
+// lastWords reads what the player is sent until the server hangs up and
+// returns the last info message.
+func (p *testPlayer) lastWords() (string, error) {
+	last := ""
+	for {
+		msg, err := p.next()
+		if err != nil {
+			if err.Error() == "connection closed" {
+				return last, nil
+			}
+			return last, err
+		}
+		if msg.MoveType == Info {
+			json.Unmarshal(msg.Data, &last)
+		}
+	}
+}
+
+// savedRecord loads the only game saved in dir.
+func savedRecord(t *testing.T, dir string) game.Record {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("saved games = %v (%v), want one", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record game.Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := game.Replay(record); err != nil {
+		t.Fatalf("the saved game does not replay: %v", err)
+	}
+	return record
+}
+
+func TestShutdownSavesRunningGames(t *testing.T) {
+	s, addr := startServer(t, nil)
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+	for _, p := range []*testPlayer{ada, bo} {
+		if _, err := p.await(State); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.Shutdown()
+	for _, p := range []*testPlayer{ada, bo} {
+		last, err := p.lastWords()
+		if err != nil {
+			t.Fatalf("%s: %v", p.name, err)
+		}
+		if !strings.Contains(last, "Goodbye") {
+			t.Errorf("%s was last told %q, want a goodbye", p.name, last)
+		}
+	}
+	record := savedRecord(t, s.SaveDir)
+	if !reflect.DeepEqual(record.Players, []string{"Ada", "Bo"}) {
+		t.Errorf("saved players = %v, want Ada and Bo", record.Players)
+	}
+	if _, err := net.Dial("tcp", addr); err == nil {
+		t.Error("the server still accepts connections after shutting down")
+	}
+}
+
+func TestShutdownReportsGamesItCannotSave(t *testing.T) {
+	// A file where the directory should be makes saving fail
+	blocked := filepath.Join(t.TempDir(), "saved")
+	if err := os.WriteFile(blocked, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, addr := startServer(t, func(s *GameServer) { s.SaveDir = blocked })
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+	if _, err := bo.await(State); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Shutdown()
+	for _, p := range []*testPlayer{ada, bo} {
+		for {
+			msg, err := p.next()
+			if err != nil {
+				break
+			}
+			var text string
+			if msg.MoveType == Info && json.Unmarshal(msg.Data, &text) == nil && strings.Contains(text, "saved") {
+				t.Errorf("%s was told %q although the game could not be saved", p.name, text)
+			}
+		}
+	}
+}
+
+func TestShutdownLetsTheHandFinish(t *testing.T) {
+	s, addr := startServer(t, func(s *GameServer) { s.ShutdownWait = time.Minute })
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+	if _, err := bo.await(State); err != nil {
+		t.Fatal(err)
+	}
+	adaDone, boDone := playInBackground(ada), playInBackground(bo)
+
+	s.Shutdown()
+	for _, done := range []<-chan error{adaDone, boDone} {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The game stops right after the next hand is dealt
+	events := savedRecord(t, s.SaveDir).Events
+	hands, dealt := 0, false
+	for _, e := range events {
+		switch e.Type {
+		case game.EventNewHand:
+			hands++
+			dealt = true
+		case game.EventDeal, game.EventStage:
+		default:
+			dealt = false
+		}
+	}
+	if hands < 2 || !dealt {
+		t.Errorf("the game was not stopped between two hands: %+v", events)
+	}
+}
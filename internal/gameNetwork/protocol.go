@@ -0,0 +1,375 @@
+package gameNetwork
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+)
+
+// ProtocolVersion is the version of the line-delimited JSON protocol spoken
+// by clients that open with a hello message. See docs/PROTOCOL.md.
+const ProtocolVersion = 1
+
+// HelloData is the payload of the hello message a JSON client opens with.
+type HelloData struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// WelcomeData answers a hello.
+type WelcomeData struct {
+	Version int        `json:"version"`
+	Name    string     `json:"name"`
+	Rules   game.Rules `json:"rules"`
+	Summary string     `json:"summary"`
+}
+
+// EventData carries one game event and its human readable text. Cards of
+// other players' private events are left out.
+type EventData struct {
+	Event game.Event `json:"event"`
+	Text  string     `json:"text"`
+}
+
+// PromptData asks a player for a move. Kind is one of PokerToss, HandClaim,
+// ChicagoCall or TrickPlay.
+type PromptData struct {
+	Kind       MessageType   `json:"kind"`
+	Seat       int           `json:"seat"`
+	LegalPlays []int         `json:"legal_plays,omitempty"`
+	BestClaim  game.HandRank `json:"best_claim,omitempty"`
+	Timeout    int           `json:"timeout,omitempty"` // seconds to answer in, zero for no limit
+}
+
+// TableInfo describes a table in the lobby.
+type TableInfo struct {
+	Name       string   `json:"name"`
+	Players    []string `json:"players"`
+	MaxPlayers int      `json:"max_players"`
+	Ready      int      `json:"ready"` // players who voted to start
+	Started    bool     `json:"started"`
+	Away       []string `json:"away,omitempty"` // players whose moves are made for them until they are back
+}
+
+// TableData names a table to create, join or that was left. MaxPlayers is
+// only read when creating a table; zero seats as many as the rules allow.
+type TableData struct {
+	Name       string `json:"name"`
+	MaxPlayers int    `json:"max_players,omitempty"`
+}
+
+// SeatData tells a player the seat they hold and the token that reclaims it
+// if their connection drops. A resume message carries just the token.
+type SeatData struct {
+	Table string `json:"table,omitempty"`
+	Seat  int    `json:"seat"`
+	Token string `json:"token"`
+}
+
+// incoming is a message read from a JSON client, whose payload is decoded
+// once its type is known.
+type incoming struct {
+	MoveType MessageType     `json:"move_type"`
+	ID       int             `json:"id"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// inputBuffer is how many moves a client may send ahead of being asked.
+const inputBuffer = 16
+
+// outputBuffer is how many writes may wait for a client that reads slowly
+// before it is hung up on, rather than holding up its table.
+const outputBuffer = 256
+
+// writeTimeout bounds how long a single write to a client may take.
+const writeTimeout = 10 * time.Second
+
+// errTimeout reports that a player did not answer a prompt in time.
+var errTimeout = errors.New("timed out waiting for a move")
+
+// errVacated interrupts a prompt when a player gets up from the table.
+var errVacated = errors.New("a player left the table")
+
+// errHalted interrupts a prompt when the game is stopped.
+var errHalted = errors.New("the game was stopped")
+
+// errSlowClient reports a client that fell too far behind on its messages.
+var errSlowClient = errors.New("the client is not keeping up with its messages")
+
+// Client is a connected player. Its connection goroutine is the only one
+// reading from the connection and its writer goroutine the only one writing
+// to it; everyone else goes through the input and output channels.
+type Client struct {
+	name   string
+	conn   net.Conn
+	reader *bufio.Reader
+	json   bool // speaks the JSON protocol rather than plain text
+	player *player.Player
+	table  *Table // table the client sits at, guarded by GameServer.mu
+
+	// input carries the lines meant for the game from the connection's reader
+	// to the table prompting the player. It is closed when the client goes.
+	input chan string
+
+	// output carries everything written to the client to its writer.
+	output    chan string
+	closing   chan struct{} // closed to have the writer flush and hang up
+	closeOnce sync.Once
+	hungUp    chan struct{} // closed once the writer has hung up
+}
+
+func newClient(conn net.Conn) *Client {
+	c := &Client{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		input:   make(chan string, inputBuffer),
+		output:  make(chan string, outputBuffer),
+		closing: make(chan struct{}),
+		hungUp:  make(chan struct{}),
+	}
+	go c.writeLoop()
+	return c
+}
+
+// writeLoop sends the client's output in order. Once the client is closed
+// it sends what is still queued and hangs up, which also ends the reads on
+// the connection.
+func (c *Client) writeLoop() {
+	defer close(c.hungUp)
+	defer c.conn.Close()
+	defer c.close()
+	for {
+		select {
+		case text := <-c.output:
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if _, err := io.WriteString(c.conn, text); err != nil {
+				return
+			}
+		case <-c.closing:
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			for {
+				select {
+				case text := <-c.output:
+					if _, err := io.WriteString(c.conn, text); err != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// close hangs up on the client once what was written to it has been sent.
+func (c *Client) close() {
+	c.closeOnce.Do(func() { close(c.closing) })
+}
+
+// readLine reads the client's next line without its line ending.
+func (c *Client) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// deliver passes a line to the game. Lines beyond what the buffer holds are
+// dropped rather than holding up the connection.
+func (c *Client) deliver(line string) {
+	select {
+	case c.input <- line:
+	default:
+		c.fail(0, errors.New("too many moves sent ahead, wait for your turn"))
+	}
+}
+
+// nextLine waits for a line meant for the game until timeout fires, a
+// player gets up from the table or the game is halted. A nil timeout waits
+// for as long as the client stays connected.
+func (c *Client) nextLine(timeout <-chan time.Time, vacated, halt <-chan struct{}) (string, error) {
+	select {
+	case line, ok := <-c.input:
+		if !ok {
+			return "", io.EOF
+		}
+		return line, nil
+	case <-timeout:
+		return "", errTimeout
+	case <-vacated:
+		return "", errVacated
+	case <-halt:
+		return "", errHalted
+	}
+}
+
+// drain throws away lines typed before the client was asked for a move.
+func (c *Client) drain() {
+	for {
+		select {
+		case _, ok := <-c.input:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// write queues raw text for the client. It never blocks: a client too far
+// behind to take more is hung up on instead.
+func (c *Client) write(text string) error {
+	select {
+	case <-c.closing:
+		return net.ErrClosed
+	default:
+	}
+	select {
+	case c.output <- text:
+		return nil
+	default:
+		fmt.Printf("Player %s fell behind on their messages and is disconnected\n", c.name)
+		c.close()
+		return errSlowClient
+	}
+}
+
+// send writes a message as one line of JSON.
+func (c *Client) send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.write(string(data) + "\n")
+}
+
+// info tells the client something in the form it understands: an info
+// message for JSON clients, a line of text otherwise.
+func (c *Client) info(text string) error {
+	text = strings.TrimRight(text, "\n")
+	if c.json {
+		return c.send(Message{MoveType: Info, Data: strings.TrimSpace(text)})
+	}
+	return c.write(text + "\n")
+}
+
+// fail reports an error to the client, answering the prompt with the given
+// id if there is one.
+func (c *Client) fail(id int, err error) error {
+	if c.json {
+		return c.send(Message{MoveType: ProtocolError, ID: id, Data: err.Error()})
+	}
+	return c.write(fmt.Sprintf("\n%v\n", err))
+}
+
+// maxPlayerName bounds the length of player names, which are shown in every
+// message about the player.
+const maxPlayerName = 24
+
+func validPlayerName(name string) error {
+	if name == "" {
+		return errors.New("empty username")
+	}
+	if utf8.RuneCountInString(name) > maxPlayerName {
+		return fmt.Errorf("usernames are at most %d characters", maxPlayerName)
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return errors.New("usernames may not contain control characters")
+		}
+	}
+	return nil
+}
+
+// handshake greets a new connection and reads the player's name. A first
+// line holding a hello message switches the client to the JSON protocol;
+// anything else is taken as a name typed into netcat.
+func (c *Client) handshake(rules game.Rules) (string, error) {
+	c.write("\n=== Welcome to Chicago Poker ===\nEnter your username: ")
+	line, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+		c.name = strings.TrimSpace(line)
+		if err := validPlayerName(c.name); err != nil {
+			c.write(fmt.Sprintf("Invalid username: %v\n", err))
+			return "", err
+		}
+		c.write(fmt.Sprintf("Welcome, %s!\n", c.name))
+		return c.name, nil
+	}
+
+	// The banner left the prompt line open; JSON starts on a line of its own
+	c.json = true
+	c.write("\n")
+	var msg incoming
+	var hello HelloData
+	if err := json.Unmarshal([]byte(line), &msg); err != nil || msg.MoveType != Hello {
+		err = fmt.Errorf("expected a hello message: %v", err)
+		c.fail(0, err)
+		return "", err
+	}
+	if err := json.Unmarshal(msg.Data, &hello); err != nil {
+		err = fmt.Errorf("invalid hello: %v", err)
+		c.fail(0, err)
+		return "", err
+	}
+	if hello.Version != ProtocolVersion {
+		err := fmt.Errorf("unsupported protocol version %d, the server speaks %d", hello.Version, ProtocolVersion)
+		c.fail(0, err)
+		return "", err
+	}
+	c.name = strings.TrimSpace(hello.Name)
+	if err := validPlayerName(c.name); err != nil {
+		c.fail(0, err)
+		return "", err
+	}
+	c.send(Message{PlayerName: c.name, MoveType: Welcome, Data: WelcomeData{
+		Version: ProtocolVersion,
+		Name:    c.name,
+		Rules:   rules,
+		Summary: rules.Summary(),
+	}})
+	return c.name, nil
+}
+
+// readAction reads a JSON client's reply to the prompt with the given id.
+// Messages that are not that reply are answered with an error and skipped.
+func (c *Client) readAction(id int, timeout <-chan time.Time, vacated, halt <-chan struct{}) (game.Action, error) {
+	for {
+		line, err := c.nextLine(timeout, vacated, halt)
+		if err != nil {
+			return game.Action{}, err
+		}
+		var msg incoming
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			c.fail(id, fmt.Errorf("invalid message: %v", err))
+			continue
+		}
+		if msg.MoveType != PlayerAction || msg.ID != id {
+			c.fail(id, fmt.Errorf("expected an action replying to prompt %d", id))
+			continue
+		}
+		var action game.Action
+		if err := json.Unmarshal(msg.Data, &action); err != nil {
+			c.fail(id, fmt.Errorf("invalid action: %v", err))
+			continue
+		}
+		return action, nil
+	}
+}
@@ -0,0 +1,157 @@
+package gameNetwork
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+// connect opens a connection that has not said hello yet.
+func connect(t *testing.T, addr, name string) *testPlayer {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testPlayer{name: name, conn: conn, scanner: bufio.NewScanner(conn)}
+}
+
+// awaitEvent reads until the player is told of an event of the given type
+// in the given seat.
+func awaitEvent(t *testing.T, p *testPlayer, eventType game.EventType, seat int) game.Event {
+	t.Helper()
+	for {
+		msg, err := p.await(GameEvent)
+		if err != nil {
+			t.Fatalf("%s: waiting for %s of seat %d: %v", p.name, eventType, seat, err)
+		}
+		var data EventData
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		if data.Event.Type == eventType && data.Event.Player == seat {
+			return data.Event
+		}
+	}
+}
+
+func TestHelloIsChecked(t *testing.T) {
+	addr := testServer(t, nil)
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"future version", `{"move_type":"hello","data":{"version":2,"name":"Ada"}}`, "unsupported protocol version 2"},
+		{"no version", `{"move_type":"hello","data":{"name":"Ada"}}`, "unsupported protocol version 0"},
+		{"malformed data", `{"move_type":"hello","data":"Ada"}`, "invalid hello"},
+		{"not a hello", `{"move_type":"ready"}`, "expected a hello message"},
+		{"not JSON", `{"move_type":`, "expected a hello message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := connect(t, addr, "Ada")
+			fmt.Fprintln(p.conn, tt.line)
+			expectError(t, p, tt.want)
+			if _, err := p.next(); err == nil {
+				t.Errorf("the connection stayed open after a bad hello")
+			}
+		})
+	}
+}
+
+func TestPlainLinesFallBackToText(t *testing.T) {
+	addr := testServer(t, nil)
+	p := connect(t, addr, "Ada")
+	fmt.Fprintln(p.conn, "Ada")
+	for p.scanner.Scan() {
+		line := p.scanner.Text()
+		if strings.HasPrefix(line, "{") {
+			t.Fatalf("a text client was sent JSON: %s", line)
+		}
+		if strings.Contains(line, "Welcome, Ada!") {
+			return
+		}
+	}
+	t.Fatalf("no welcome for a text client: %v", p.scanner.Err())
+}
+
+func TestActionsMustAnswerTheirPrompt(t *testing.T) {
+	addr := testServer(t, nil)
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+	playInBackground(ada)
+
+	msg, err := bo.await(Prompt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prompt PromptData
+	json.Unmarshal(msg.Data, &prompt)
+	bo.send(PlayerAction, msg.ID+1, game.StandPat(prompt.Seat))
+	expectError(t, bo, fmt.Sprintf("replying to prompt %d", msg.ID))
+
+	// The prompt is still open and takes the right reply
+	bo.send(PlayerAction, msg.ID, game.StandPat(prompt.Seat))
+	awaitEvent(t, bo, game.EventStandPat, prompt.Seat)
+}
+
+func TestOthersSeeNoneOfYourCards(t *testing.T) {
+	addr := testServer(t, nil)
+	ada, bo := dial(t, addr, "Ada"), dial(t, addr, "Bo")
+	sitDown(t, ada, bo)
+
+	// Everyone is dealt five cards, and sees only their own
+	var states [2]game.Snapshot
+	for i, p := range []*testPlayer{ada, bo} {
+		seen := map[int][]cards.Card{}
+		for {
+			msg, err := p.next()
+			if err != nil {
+				t.Fatalf("%s: %v", p.name, err)
+			}
+			if msg.MoveType == State {
+				json.Unmarshal(msg.Data, &states[i])
+				break
+			}
+			var data EventData
+			if msg.MoveType == GameEvent && json.Unmarshal(msg.Data, &data) == nil && data.Event.Type == game.EventDeal {
+				seen[data.Event.Player] = data.Event.Cards
+			}
+		}
+		if len(seen) != 2 || len(seen[i]) != game.HandSize || len(seen[1-i]) != 0 {
+			t.Errorf("%s in seat %d saw the deals %v", p.name, i, seen)
+		}
+	}
+	if states[0].Seat != 0 || states[1].Seat != 1 {
+		t.Fatalf("Ada and Bo sit in seats %d and %d", states[0].Seat, states[1].Seat)
+	}
+
+	// The player to act tosses two cards; only they see which, and what
+	// they draw
+	mover, other := ada, bo
+	seat := states[0].Turn
+	if seat == 1 {
+		mover, other = bo, ada
+	}
+	msg, err := mover.await(Prompt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mover.send(PlayerAction, msg.ID, game.Toss(seat, 0, 1))
+	for _, eventType := range []game.EventType{game.EventToss, game.EventDraw} {
+		if e := awaitEvent(t, mover, eventType, seat); len(e.Cards) != 2 {
+			t.Errorf("%s sees their own %s as %v", mover.name, eventType, e.Cards)
+		}
+		if e := awaitEvent(t, other, eventType, seat); len(e.Cards) != 0 {
+			t.Errorf("%s sees the %s of %s: %v", other.name, eventType, mover.name, e.Cards)
+		}
+	}
+}
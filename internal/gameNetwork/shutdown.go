@@ -0,0 +1,151 @@
+package gameNetwork
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+)
+
+// errShuttingDown turns away players once Shutdown has been called.
+var errShuttingDown = errors.New("the server is shutting down")
+
+// Shutdown stops the server gracefully. It stops accepting players and tells
+// every table; running games get ShutdownWait to finish the hand being
+// played, after which those still in progress are saved to SaveDir. Everyone
+// is then told goodbye and hung up on once their last messages are out.
+func (s *GameServer) Shutdown() {
+	s.mu.Lock()
+	s.closing = true
+	listeners := s.listeners
+	web := s.web
+	tables := make([]*Table, 0, len(s.tables))
+	for _, t := range s.tables {
+		tables = append(tables, t)
+	}
+	s.mu.Unlock()
+
+	fmt.Println("Shutting down...")
+	for _, ln := range listeners {
+		ln.Close()
+	}
+	if web != nil {
+		web.Close()
+	}
+	for _, t := range tables {
+		t.shutdown(s.ShutdownWait)
+	}
+	s.games.Wait()
+
+	s.mu.Lock()
+	clients := make([]*Client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+	for _, c := range clients {
+		c.info("The server is shutting down. Goodbye!")
+		c.close()
+	}
+	for _, c := range clients {
+		<-c.hungUp
+	}
+	fmt.Println("Server stopped.")
+}
+
+// shutdown tells the table the server is going down. A game that has not
+// started never will; a running one is stopped once its hand is over, or
+// after wait at the latest.
+func (t *Table) shutdown(wait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case !t.started:
+		t.stop()
+		if t.timer != nil {
+			t.timer.Stop()
+			t.timer = nil
+		}
+		t.broadcastLocked("The server is shutting down, so this game will not start.")
+	case wait <= 0:
+		t.broadcastLocked("The server is shutting down; the game is stopped here.")
+		t.stop()
+	default:
+		t.finishing = true
+		t.broadcastLocked(fmt.Sprintf("The server is shutting down once this hand is over, in %v at the most.", wait))
+		time.AfterFunc(wait, t.stop)
+	}
+}
+
+// stop halts the game wherever it is waiting.
+func (t *Table) stop() {
+	t.haltOnce.Do(func() { close(t.halt) })
+}
+
+func (t *Table) halted() bool {
+	select {
+	case <-t.halt:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Table) isFinishing() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.finishing
+}
+
+// handOver reports whether the events of a move finished a hand.
+func handOver(events []game.Event) bool {
+	for _, e := range events {
+		if e.Type == game.EventNewHand {
+			return true
+		}
+	}
+	return false
+}
+
+// save writes the record of a game halted before it was over to the
+// server's SaveDir, so it can be replayed later, and tells the players.
+func (t *Table) save() {
+	dir := t.server.SaveDir
+	if dir == "" {
+		fmt.Printf("Game at table %s stopped before it was over\n", t.Name)
+		return
+	}
+	record, err := json.MarshalIndent(t.game.Record(), "", "  ")
+	if err != nil {
+		fmt.Printf("Error marshaling game record: %v\n", err)
+		return
+	}
+	name := fmt.Sprintf("%s-%s.json", fileName(t.Name), time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	err = os.MkdirAll(dir, 0o755)
+	if err == nil {
+		err = os.WriteFile(path, record, 0o644)
+	}
+	if err != nil {
+		fmt.Printf("Error saving the game at table %s: %v\n", t.Name, err)
+		return
+	}
+	fmt.Printf("Saved the game at table %s to %s\n", t.Name, path)
+	t.broadcastMessage(fmt.Sprintf("The game was saved as %s.", name))
+}
+
+// fileName makes a table name safe to use in a file name.
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,489 @@
+package gameNetwork
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antongollbo123/chicago-poker/internal/game"
+	"github.com/antongollbo123/chicago-poker/internal/player"
+)
+
+// errBadToken reports a resume token that matches no seat it can reclaim.
+var errBadToken = errors.New("unknown or expired resume token")
+
+// Table is a named game in the lobby. Players sit down and vote to start;
+// the game starts once they all have, or LobbyWait after most of them have,
+// or as soon as the table is full. It then runs in its own goroutine until
+// it is over.
+//
+// Every seat comes with a resume token. A player whose connection drops in
+// the middle of the game can take their seat back with it within
+// ReconnectGrace; until then the game waits for them when it is their turn.
+//
+// A player who runs out of time on AwayAfter turns in a row is marked away:
+// TimeoutPolicy then makes their moves without waiting for them until they
+// say they are back.
+type Table struct {
+	Name           string
+	Rules          game.Rules
+	LobbyWait      time.Duration
+	TurnTimeout    time.Duration
+	TurnNotices    []time.Duration
+	TimeoutPolicy  game.Fallback
+	AwayAfter      int
+	ReconnectGrace time.Duration
+
+	server *GameServer
+	game   *game.Game // changed by the game loop only, while holding mu
+
+	mu      sync.Mutex // guards everything below, and the game as described above
+	seats   []*Client  // client in each seat, nil while nobody is there
+	ready   map[*Client]bool
+	started bool
+	timer   *time.Timer               // starts the game once most players are ready
+	tokens  map[string]*player.Player // player each resume token belongs to
+	away    map[int]time.Time         // seats of dropped players, until when they may return
+	missed  map[int]int               // turns in a row each seat ran out of time on
+	idle    map[int]bool              // seats marked away for running out of time
+
+	finishing bool // the server is shutting down once the hand is over
+
+	resumed  chan struct{} // a dropped player has taken their seat back
+	vacated  chan struct{} // a player has got up or dropped
+	halt     chan struct{} // closed to stop the game where it is
+	haltOnce sync.Once
+
+	promptID int // id of the last prompt sent, used by the game loop only
+}
+
+func newTable(s *GameServer, name string, rules game.Rules) *Table {
+	g := game.NewGame([]*player.Player{})
+	g.Rules = rules
+	return &Table{
+		Name:           name,
+		Rules:          rules,
+		LobbyWait:      s.LobbyWait,
+		TurnTimeout:    s.TurnTimeout,
+		TurnNotices:    s.TurnNotices,
+		TimeoutPolicy:  s.TimeoutPolicy,
+		AwayAfter:      s.AwayAfter,
+		ReconnectGrace: s.ReconnectGrace,
+		server:         s,
+		game:           g,
+		ready:          make(map[*Client]bool),
+		tokens:         make(map[string]*player.Player),
+		away:           make(map[int]time.Time),
+		missed:         make(map[int]int),
+		idle:           make(map[int]bool),
+		resumed:        make(chan struct{}, 1),
+		vacated:        make(chan struct{}, 1),
+		halt:           make(chan struct{}),
+	}
+}
+
+// newToken returns a random resume token.
+func newToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// signal wakes up the game loop if it waits on ch.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// info describes the table for the lobby.
+func (t *Table) info() TableInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.infoLocked()
+}
+
+func (t *Table) infoLocked() TableInfo {
+	info := TableInfo{
+		Name:       t.Name,
+		Players:    make([]string, len(t.game.Players)),
+		MaxPlayers: t.Rules.MaxPlayers,
+		Ready:      len(t.ready),
+		Started:    t.started,
+	}
+	for i, p := range t.game.Players {
+		info.Players[i] = p.Name
+		if t.idle[i] {
+			info.Away = append(info.Away, p.Name)
+		}
+	}
+	return info
+}
+
+func (t *Table) isStarted() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.started
+}
+
+// join seats a client. The game starts straight away if that fills the
+// table.
+func (t *Table) join(c *Client) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := player.NewPlayer(c.name)
+	if err := t.game.AddPlayer(p); err != nil {
+		return err
+	}
+	c.player = p
+	t.seats = append(t.seats, c)
+	token := newToken()
+	t.tokens[token] = p
+	fmt.Printf("Player %s has joined table %s.\n", c.name, t.Name)
+	t.sendSeatLocked(c, len(t.seats)-1, token)
+	t.announceLocked(fmt.Sprintf("%s has joined table %s (%d/%d seats taken). Type ready to vote to start.", c.name, t.Name, len(t.seats), t.Rules.MaxPlayers))
+	if len(t.seats) == t.Rules.MaxPlayers {
+		t.startLocked()
+	}
+	return nil
+}
+
+// sendSeatLocked tells a client which seat they hold and the token to
+// reclaim it with.
+func (t *Table) sendSeatLocked(c *Client, seat int, token string) {
+	if c.json {
+		c.send(Message{PlayerName: c.name, MoveType: Seated, Data: SeatData{Table: t.Name, Seat: seat, Token: token}})
+		return
+	}
+	c.info(fmt.Sprintf("Your resume token is %s. If your connection drops during the game, reconnect within %v and type: resume %s", token, t.ReconnectGrace, token))
+}
+
+// leave gets a client up from the table. Before the game starts their seat
+// is given up; once it is running, moves are made for them instead. It
+// reports whether the table is empty and has no game to finish, so can be
+// closed.
+func (t *Table) leave(c *Client) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seat := t.seatLocked(c)
+	if seat < 0 {
+		return false
+	}
+	if t.started {
+		t.seats[seat] = nil
+		t.revokeLocked(seat)
+		signal(t.vacated)
+		t.broadcastLocked(fmt.Sprintf("%s has left the table; their moves will be made for them.", c.name))
+		return false
+	}
+	return t.giveUpSeatLocked(c, seat)
+}
+
+// drop handles a client whose connection is gone. Once the game is running
+// their seat is kept for them for ReconnectGrace. It reports whether the
+// table can be closed, like leave.
+func (t *Table) drop(c *Client) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seat := t.seatLocked(c)
+	if seat < 0 {
+		return false
+	}
+	if !t.started {
+		return t.giveUpSeatLocked(c, seat)
+	}
+	t.seats[seat] = nil
+	t.away[seat] = time.Now().Add(t.ReconnectGrace)
+	signal(t.vacated)
+	t.broadcastLocked(fmt.Sprintf("%s lost their connection; they have %v to come back.", c.name, t.ReconnectGrace))
+	return false
+}
+
+// resume seats a client in the place of the dropped player the token
+// belongs to. It reports false if the token is not one of this table's.
+func (t *Table) resume(c *Client, token string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.tokens[token]
+	if !ok {
+		return false, nil
+	}
+	seat := t.playerSeatLocked(p)
+	until, away := t.away[seat]
+	if t.seats[seat] != nil {
+		return true, errors.New("that seat is still connected")
+	}
+	if !away || time.Now().After(until) {
+		return true, errBadToken
+	}
+
+	delete(t.away, seat)
+	delete(t.missed, seat)
+	delete(t.idle, seat)
+	t.seats[seat] = c
+	c.player = p
+	signal(t.resumed)
+	fmt.Printf("Player %s has resumed seat %d at table %s.\n", c.name, seat, t.Name)
+	t.announceLocked(fmt.Sprintf("%s is back.", p.Name))
+	t.sendSeatLocked(c, seat, token)
+	t.sendSnapshotLocked(c, seat)
+	return true, nil
+}
+
+// missTurn counts a turn a player ran out of time on, and marks them away
+// once they have done so AwayAfter times in a row.
+func (t *Table) missTurn(c *Client, seat int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.missed[seat]++
+	if t.AwayAfter == 0 || t.missed[seat] < t.AwayAfter {
+		c.info("Time is up; a move was made for you.")
+		return
+	}
+	t.idle[seat] = true
+	fmt.Printf("Player %s is marked away at table %s.\n", c.name, t.Name)
+	t.announceLocked(fmt.Sprintf("%s is away; their moves will be made for them.", c.name))
+	if c.json {
+		c.info("You keep running out of time, so you are marked away; your moves will be made for you until you send back.")
+	} else {
+		c.info("You keep running out of time, so you are marked away; your moves will be made for you until you type: back")
+	}
+}
+
+// answered notes that a player made a move in time.
+func (t *Table) answered(seat int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.missed, seat)
+}
+
+// isIdle reports whether a seat is marked away.
+func (t *Table) isIdle(seat int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.idle[seat]
+}
+
+// back lets a player who was marked away make their own moves again.
+func (t *Table) back(c *Client) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seat := t.seatLocked(c)
+	if seat < 0 {
+		return errNoTable
+	}
+	if !t.idle[seat] {
+		return errors.New("you are not marked away")
+	}
+	delete(t.idle, seat)
+	delete(t.missed, seat)
+	fmt.Printf("Player %s is back at table %s.\n", c.name, t.Name)
+	t.announceLocked(fmt.Sprintf("%s is back.", c.name))
+	return nil
+}
+
+// awaitReturn waits for a dropped player to take their seat back, until
+// their grace period runs out. It returns nil if they do not.
+func (t *Table) awaitReturn(seat int) *Client {
+	announced := false
+	for {
+		t.mu.Lock()
+		c := t.seats[seat]
+		until, away := t.away[seat]
+		if c == nil && away && time.Now().After(until) {
+			delete(t.away, seat)
+			t.revokeLocked(seat)
+			t.broadcastLocked(fmt.Sprintf("%s did not come back; their moves will be made for them.", t.game.Players[seat].Name))
+			away = false
+		}
+		if c == nil && away && !announced {
+			t.broadcastLocked(fmt.Sprintf("Waiting up to %v for %s to come back...", time.Until(until).Round(time.Second), t.game.Players[seat].Name))
+			announced = true
+		}
+		t.mu.Unlock()
+		if c != nil || !away {
+			return c
+		}
+
+		wait := time.NewTimer(time.Until(until))
+		select {
+		case <-t.resumed:
+		case <-wait.C:
+		case <-t.halt:
+			wait.Stop()
+			return nil
+		}
+		wait.Stop()
+	}
+}
+
+// sendSnapshotLocked shows a client the whole game from their seat.
+func (t *Table) sendSnapshotLocked(c *Client, seat int) {
+	snapshot := t.game.Snapshot(seat)
+	if c.json {
+		c.send(Message{PlayerName: c.name, MoveType: State, Data: snapshot})
+		return
+	}
+	text := fmt.Sprintf("You are back at table %s. Stage: %v. Scores:", t.Name, snapshot.Stage)
+	for _, p := range snapshot.Players {
+		text += fmt.Sprintf(" %s %d,", p.Name, p.Score)
+	}
+	text = strings.TrimSuffix(text, ",") + "\nYour hand:"
+	for i, card := range snapshot.Hand {
+		text += fmt.Sprintf(" [%d] %v", i, card)
+	}
+	if len(snapshot.Trick) > 0 {
+		text += "\nOn the table:"
+		for i, card := range snapshot.Trick {
+			if card.Rank != 0 {
+				text += fmt.Sprintf(" %s %v", snapshot.Players[i].Name, card)
+			}
+		}
+	}
+	c.info(text)
+}
+
+// revokeLocked forgets the resume token of a seat.
+func (t *Table) revokeLocked(seat int) {
+	for token, p := range t.tokens {
+		if p == t.game.Players[seat] {
+			delete(t.tokens, token)
+		}
+	}
+}
+
+func (t *Table) playerSeatLocked(p *player.Player) int {
+	for i, seated := range t.game.Players {
+		if seated == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// giveUpSeatLocked removes a client from a table whose game has not
+// started.
+func (t *Table) giveUpSeatLocked(c *Client, seat int) bool {
+	t.revokeLocked(seat)
+	t.game.RemovePlayer(seat)
+	t.seats = append(t.seats[:seat], t.seats[seat+1:]...)
+	delete(t.ready, c)
+	if len(t.seats) == 0 {
+		return true
+	}
+	t.announceLocked(fmt.Sprintf("%s has left table %s (%d/%d seats taken).", c.name, t.Name, len(t.seats), t.Rules.MaxPlayers))
+	t.checkVotesLocked()
+	return false
+}
+
+// vote records that a client is ready to start.
+func (t *Table) vote(c *Client) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started {
+		return game.ErrGameStarted
+	}
+	if t.ready[c] {
+		return fmt.Errorf("you have already voted to start")
+	}
+	t.ready[c] = true
+	t.announceLocked(fmt.Sprintf("%s is ready (%d/%d players ready).", c.name, len(t.ready), len(t.seats)))
+	t.checkVotesLocked()
+	return nil
+}
+
+// checkVotesLocked starts the game once every player is ready, and sets the
+// clock running once most of them are.
+func (t *Table) checkVotesLocked() {
+	players := len(t.seats)
+	if t.started || players < t.Rules.MinPlayers {
+		return
+	}
+	switch votes := len(t.ready); {
+	case votes == players:
+		t.startLocked()
+	case votes*2 > players && t.timer == nil:
+		t.broadcastLocked(fmt.Sprintf("The game starts in %v unless everyone is ready first.", t.LobbyWait))
+		t.timer = time.AfterFunc(t.LobbyWait, t.startWhenMostReady)
+	}
+}
+
+func (t *Table) startWhenMostReady() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timer = nil
+	if !t.started && len(t.seats) >= t.Rules.MinPlayers && len(t.ready)*2 > len(t.seats) {
+		t.startLocked()
+	}
+}
+
+func (t *Table) startLocked() {
+	if t.started || t.halted() {
+		return
+	}
+	t.started = true
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	t.server.games.Add(1)
+	go t.run()
+}
+
+func (t *Table) seatLocked(c *Client) int {
+	for i, seated := range t.seats {
+		if seated == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// client returns the client in a seat, or nil if they have left.
+func (t *Table) client(seat int) *Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.seats[seat]
+}
+
+// members returns the clients still at the table, indexed by seat.
+func (t *Table) members() []*Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*Client(nil), t.seats...)
+}
+
+// broadcastMessage tells everyone at the table something.
+func (t *Table) broadcastMessage(text string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.broadcastLocked(text)
+}
+
+func (t *Table) broadcastLocked(text string) {
+	for _, c := range t.seats {
+		if c != nil {
+			c.info(text)
+		}
+	}
+}
+
+// announceLocked tells everyone at the table about a change to it, with the
+// table's new description for JSON clients.
+func (t *Table) announceLocked(text string) {
+	info := t.infoLocked()
+	for _, c := range t.seats {
+		if c == nil {
+			continue
+		}
+		if c.json {
+			c.send(Message{PlayerName: c.name, MoveType: TableUpdate, Data: info})
+		}
+		c.info(text)
+	}
+}
@@ -0,0 +1,44 @@
+package gameNetwork
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+)
+
+// indexHTML is the browser client. It needs nothing but the server, so it
+// also works on a LAN without internet access.
+//
+//go:embed web/index.html
+var indexHTML []byte
+
+// serveWeb serves the browser client and accepts WebSocket players on /ws
+// next to the TCP listener. They join the same table and speak the same
+// protocol as TCP clients.
+func (s *GameServer) serveWeb() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", s.handleWebsocket)
+	mux.HandleFunc("/", serveIndex)
+	srv := &http.Server{Addr: s.WebAddr, Handler: mux}
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		return
+	}
+	s.web = srv
+	s.mu.Unlock()
+
+	fmt.Printf("Browsers can play at http://localhost%s/\n", s.WebAddr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fmt.Println("Error starting the web server:", err)
+	}
+}
+
+func serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(indexHTML)
+}
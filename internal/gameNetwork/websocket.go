@@ -0,0 +1,261 @@
+package gameNetwork
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is the key suffix RFC 6455 uses to prove a handshake was read.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxFrameSize bounds the frames a browser may send; game messages are tiny.
+const maxFrameSize = 1 << 16
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// wsConn carries the game protocol over a WebSocket. Each write is sent as a
+// text frame and the payloads of incoming frames are read as one stream, so
+// browsers send the same lines of JSON, or text, as TCP clients.
+type wsConn struct {
+	net.Conn
+	reader    *bufio.Reader
+	remaining int64 // payload bytes left in the frame being read
+	mask      [4]byte
+	maskPos   int
+	closed    bool // the peer sent a close frame
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+func (s *GameServer) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgradeWebsocket(w, r, s.WebOrigins)
+	if err != nil {
+		fmt.Printf("WebSocket upgrade from %s failed: %v\n", r.RemoteAddr, err)
+		return
+	}
+	s.handleConnection(newClient(conn))
+}
+
+// upgradeWebsocket answers a WebSocket handshake from an allowed origin and
+// takes over the connection it arrived on.
+func upgradeWebsocket(w http.ResponseWriter, r *http.Request, origins []string) (net.Conn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "expected a WebSocket upgrade", http.StatusBadRequest)
+		return nil, errors.New("not a WebSocket upgrade")
+	}
+	if !allowedOrigin(r, origins) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return nil, fmt.Errorf("origin %q is not allowed", r.Header.Get("Origin"))
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("unsupported WebSocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("connection cannot be hijacked")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+	_, err = fmt.Fprintf(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", accept)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{Conn: conn, reader: rw.Reader}, nil
+}
+
+// allowedOrigin reports whether the page a handshake comes from may play:
+// one served by this server, or one of origins. Browsers always send an
+// Origin, so any other website a player has open is turned away; requests
+// without one do not come from a browser and are let in.
+func allowedOrigin(r *http.Request, origins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range origins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Read returns the payload of data frames, answering control frames on the
+// way.
+func (c *wsConn) Read(p []byte) (int, error) {
+	for c.remaining == 0 {
+		if c.closed {
+			return 0, io.EOF
+		}
+		if err := c.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.reader.Read(p)
+	for i := 0; i < n; i++ {
+		p[i] ^= c.mask[c.maskPos%4]
+		c.maskPos++
+	}
+	c.remaining -= int64(n)
+	return n, err
+}
+
+// nextFrame reads frame headers until one starts some data, handling any
+// control frames in between.
+func (c *wsConn) nextFrame() error {
+	for {
+		opcode, length, err := c.readHeader()
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case opText, opBinary, opContinuation:
+			c.remaining = length
+			if length > 0 {
+				return nil
+			}
+		case opClose, opPing, opPong:
+			payload := make([]byte, length)
+			if _, err := io.ReadFull(c.reader, payload); err != nil {
+				return err
+			}
+			for i := range payload {
+				payload[i] ^= c.mask[i%4]
+			}
+			switch opcode {
+			case opClose:
+				c.closed = true
+				c.sendClose(payload)
+				return io.EOF
+			case opPing:
+				if err := c.writeFrame(opPong, payload); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("unknown WebSocket opcode %#x", opcode)
+		}
+	}
+}
+
+func (c *wsConn) readHeader() (opcode byte, length int64, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return 0, 0, err
+	}
+	opcode = head[0] & 0x0F
+	if head[1]&0x80 == 0 {
+		return 0, 0, errors.New("client WebSocket frames must be masked")
+	}
+	length = int64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, 0, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, 0, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+	if length > maxFrameSize || length < 0 {
+		return 0, 0, fmt.Errorf("WebSocket frame of %d bytes is too large", length)
+	}
+	if _, err := io.ReadFull(c.reader, c.mask[:]); err != nil {
+		return 0, 0, err
+	}
+	c.maskPos = 0
+	return opcode, length, nil
+}
+
+// Write sends p as a single text frame.
+func (c *wsConn) Write(p []byte) (int, error) {
+	if err := c.writeFrame(opText, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	frame := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 126, byte(n>>8), byte(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	_, err := c.Conn.Write(append(frame, payload...))
+	return err
+}
+
+// sendClose sends a close frame, once, whichever side closes first.
+func (c *wsConn) sendClose(payload []byte) {
+	c.closeOnce.Do(func() {
+		c.writeFrame(opClose, payload)
+	})
+}
+
+// Close sends a close frame before closing the connection.
+func (c *wsConn) Close() error {
+	c.sendClose(nil)
+	return c.Conn.Close()
+}
@@ -0,0 +1,264 @@
+package gameNetwork
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// clientFrame builds a frame the way a browser sends it, masked.
+func clientFrame(fin bool, opcode byte, payload []byte) []byte {
+	head := opcode
+	if fin {
+		head |= 0x80
+	}
+	frame := []byte{head}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 0x80|126, byte(n>>8), byte(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	mask := [4]byte{0x12, 0x34, 0x56, 0x78}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	return frame
+}
+
+// serverFrame reads one unmasked frame sent by the server.
+func serverFrame(t *testing.T, r io.Reader) (opcode byte, payload []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		t.Fatalf("reading a frame: %v", err)
+	}
+	if head[1]&0x80 != 0 {
+		t.Errorf("the server masked its frame")
+	}
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(r, ext[:])
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatalf("reading a frame of %d bytes: %v", length, err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+// wsPipe connects a wsConn to the browser end of an in-memory connection.
+func wsPipe(t *testing.T) (*wsConn, net.Conn) {
+	server, browser := net.Pipe()
+	t.Cleanup(func() {
+		server.Close()
+		browser.Close()
+	})
+	return &wsConn{Conn: server, reader: bufio.NewReader(server)}, browser
+}
+
+// sendFrames writes frames from the browser end in the background, as
+// net.Pipe blocks until the other end reads.
+func sendFrames(browser net.Conn, frames ...[]byte) {
+	go func() {
+		for _, f := range frames {
+			if _, err := browser.Write(f); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// readLine reads a line from the WebSocket in the background.
+func readLine(ws *wsConn) <-chan string {
+	line := make(chan string, 1)
+	go func() {
+		text, _ := bufio.NewReader(ws).ReadString('\n')
+		line <- text
+	}()
+	return line
+}
+
+func TestWebsocketJoinsFragmentsAndAnswersPings(t *testing.T) {
+	ws, browser := wsPipe(t)
+	sendFrames(browser,
+		clientFrame(false, opText, []byte(`{"type":"he`)),
+		clientFrame(true, opPing, []byte("still there?")),
+		clientFrame(true, opContinuation, []byte(`llo"}`+"\n")),
+	)
+	line := readLine(ws)
+
+	if opcode, payload := serverFrame(t, browser); opcode != opPong || string(payload) != "still there?" {
+		t.Errorf("the ping was answered with %#x %q", opcode, payload)
+	}
+	if got := <-line; got != `{"type":"hello"}`+"\n" {
+		t.Errorf("read %q from the fragments", got)
+	}
+}
+
+func TestWebsocketLongPayloads(t *testing.T) {
+	ws, browser := wsPipe(t)
+	long := strings.Repeat("x", 300) + "\n"
+	sendFrames(browser, clientFrame(true, opText, []byte(long)))
+	if got := <-readLine(ws); got != long {
+		t.Errorf("read %d bytes of a %d byte frame", len(got), len(long))
+	}
+
+	for _, size := range []int{126, 300, maxFrameSize + 1} {
+		sent := bytes.Repeat([]byte("y"), size)
+		go ws.Write(sent)
+		if opcode, payload := serverFrame(t, browser); opcode != opText || !bytes.Equal(payload, sent) {
+			t.Errorf("a write of %d bytes arrived as %#x with %d bytes", size, opcode, len(payload))
+		}
+	}
+}
+
+func TestWebsocketRejectsBadFrames(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+		want  string
+	}{
+		// Headers only: the rest is never read
+		{"oversized", clientFrame(true, opText, make([]byte, maxFrameSize+1))[:10], "too large"},
+		{"unmasked", []byte{0x80 | opText, 5}, "must be masked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, browser := wsPipe(t)
+			sendFrames(browser, tt.frame)
+			_, err := ws.Read(make([]byte, 16))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Read() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebsocketAnswersClose(t *testing.T) {
+	ws, browser := wsPipe(t)
+	status := []byte{0x03, 0xE8} // 1000, normal closure
+	sendFrames(browser, clientFrame(true, opClose, status))
+	read := make(chan error, 1)
+	go func() {
+		_, err := ws.Read(make([]byte, 16))
+		read <- err
+	}()
+
+	if opcode, payload := serverFrame(t, browser); opcode != opClose || !bytes.Equal(payload, status) {
+		t.Errorf("the close frame was answered with %#x %v", opcode, payload)
+	}
+	if err := <-read; err != io.EOF {
+		t.Errorf("Read() after a close frame = %v, want EOF", err)
+	}
+	if _, err := ws.Read(make([]byte, 16)); err != io.EOF {
+		t.Errorf("a second Read() = %v, want EOF", err)
+	}
+
+	// Closing our end must not send a second close frame
+	go ws.Close()
+	if n, err := browser.Read(make([]byte, 16)); err == nil {
+		t.Errorf("Close() sent %d more bytes", n)
+	}
+}
+
+// openWebsocket sends a WebSocket handshake from a page at origin, empty for
+// none, and returns the answer and the connection to read on from.
+func openWebsocket(t *testing.T, addr, origin string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	// The key and its answer are the example in RFC 6455
+	request := "GET / HTTP/1.1\r\nHost: " + addr + "\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"
+	if origin != "" {
+		request += "Origin: " + origin + "\r\n"
+	}
+	io.WriteString(conn, request+"\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp, r
+}
+
+func TestWebsocketHandshake(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgradeWebsocket(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, "hello\n")
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("a plain request got %s, want 400", resp.Status)
+	}
+
+	resp, r := openWebsocket(t, srv.Listener.Addr().String(), "")
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("the upgrade was answered with %s %v", resp.Status, resp.Header)
+	}
+	if opcode, payload := serverFrame(t, r); opcode != opText || string(payload) != "hello\n" {
+		t.Errorf("got %#x %q after the upgrade", opcode, payload)
+	}
+	if opcode, _ := serverFrame(t, r); opcode != opClose {
+		t.Errorf("got %#x when the server hung up, want a close frame", opcode)
+	}
+}
+
+func TestWebsocketOrigins(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if conn, err := upgradeWebsocket(w, r, []string{"https://poker.example"}); err == nil {
+			conn.Close()
+		}
+	}))
+	defer srv.Close()
+	addr := srv.Listener.Addr().String()
+
+	tests := []struct {
+		origin string
+		want   int
+	}{
+		{"", http.StatusSwitchingProtocols},
+		{"http://" + addr, http.StatusSwitchingProtocols},
+		{"https://poker.example", http.StatusSwitchingProtocols},
+		{"https://evil.example", http.StatusForbidden},
+		{"http://" + addr + ".evil.example", http.StatusForbidden},
+		{"null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if resp, _ := openWebsocket(t, addr, tt.origin); resp.StatusCode != tt.want {
+			t.Errorf("a handshake from %q got %s, want %d", tt.origin, resp.Status, tt.want)
+		}
+	}
+}
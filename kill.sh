@@ -2,19 +2,38 @@
 
 # Check if a port number is provided
 if [ -z "$1" ]; then
-  echo "Usage: $0 {PORT}"
+  echo "Usage: $0 {PORT} [SECONDS]"
+  echo "Asks the server on PORT to shut down and waits up to SECONDS (default 30)"
+  echo "for running games to be saved before killing it."
   exit 1
 fi
 
 PORT=$1
+WAIT=${2:-30}
 
-# Get PIDs using lsof and kill them
-PIDS=$(lsof -ti tcp:"$PORT")
+# Get PIDs using lsof
+PIDS=$(lsof -ti tcp:"$PORT" -sTCP:LISTEN)
 
 if [ -z "$PIDS" ]; then
-  echo "No processes found on TCP port $PORT."
-else
-  echo "Killing processes on TCP port $PORT: $PIDS"
-  kill -9 $PIDS
-  echo "Processes killed."
+  echo "No processes listening on TCP port $PORT."
+  exit 0
 fi
+
+# SIGTERM lets the server tell its players, save running games and hang up
+echo "Stopping processes on TCP port $PORT: $PIDS"
+kill $PIDS
+
+for _ in $(seq "$WAIT"); do
+  ALIVE=""
+  for PID in $PIDS; do
+    kill -0 "$PID" 2>/dev/null && ALIVE="$ALIVE $PID"
+  done
+  if [ -z "$ALIVE" ]; then
+    echo "Processes stopped."
+    exit 0
+  fi
+  sleep 1
+done
+
+echo "Still running after ${WAIT}s, killing:$ALIVE"
+kill -9 $ALIVE
@@ -1,6 +1,10 @@
 package cards
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type Suit string
 type Rank int
@@ -67,3 +71,51 @@ func SuitValue(suit string) int {
 		return 0
 	}
 }
+
+// Parse reads a card written as its rank followed by its suit, such as "AH",
+// "10s", "qd" or "7♣". Ranks are 2-10, T, J, Q, K and A; suits are H, S, D
+// and C or their symbols. Case does not matter.
+func Parse(s string) (Card, error) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+	for _, suit := range []struct {
+		names []string
+		suit  Suit
+	}{
+		{[]string{"H", string(Hearts)}, Hearts},
+		{[]string{"S", string(Spades)}, Spades},
+		{[]string{"D", string(Diamonds)}, Diamonds},
+		{[]string{"C", string(Clubs)}, Clubs},
+	} {
+		for _, name := range suit.names {
+			rank, ok := strings.CutSuffix(text, name)
+			if !ok {
+				continue
+			}
+			if r, ok := parseRank(rank); ok {
+				return Card{Suit: suit.suit, Rank: r}, nil
+			}
+			return Card{}, fmt.Errorf("unknown rank in card %q", s)
+		}
+	}
+	return Card{}, fmt.Errorf("unknown suit in card %q", s)
+}
+
+func parseRank(s string) (Rank, bool) {
+	switch s {
+	case "T":
+		return Ten, true
+	case "J":
+		return Jack, true
+	case "Q":
+		return Queen, true
+	case "K":
+		return King, true
+	case "A":
+		return Ace, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < int(Two) || n > int(Ten) {
+		return 0, false
+	}
+	return Rank(n), true
+}
@@ -1 +1,31 @@
 package cards_test
+
+import (
+	"testing"
+
+	"github.com/antongollbo123/chicago-poker/pkg/cards"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want cards.Card
+	}{
+		{"AH", cards.Card{Suit: cards.Hearts, Rank: cards.Ace}},
+		{"10s", cards.Card{Suit: cards.Spades, Rank: cards.Ten}},
+		{"td", cards.Card{Suit: cards.Diamonds, Rank: cards.Ten}},
+		{"2♣", cards.Card{Suit: cards.Clubs, Rank: cards.Two}},
+		{" qh ", cards.Card{Suit: cards.Hearts, Rank: cards.Queen}},
+	}
+	for _, tt := range tests {
+		got, err := cards.Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "A", "1H", "11S", "ZH", "AX"} {
+		if _, err := cards.Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", bad)
+		}
+	}
+}
@@ -37,15 +37,16 @@ echo "==================================="
 echo "HOW TO PLAY:"
 echo "==================================="
 echo ""
-echo "1. Open 2 new terminals and connect:"
+echo "1. Open 2-8 new terminals and connect:"
 echo "   nc localhost 8080"
 echo ""
 echo "2. Enter usernames when prompted"
 echo ""
-echo "3. Game starts automatically with 2 players"
+echo "3. In the lobby, one player types 'create <table>', the others 'join <table>'"
+echo "   The game starts once everyone at the table has typed 'ready'"
 echo ""
 echo "4. Gameplay:"
-echo "   • Poker Round: Type card indices to toss (e.g., '0 2' or press Enter to keep all)"
+echo "   • Exchange: Type card indices to toss (e.g., '0 2' or press Enter to stand pat)"
 echo "   • Trick Round: Type a single card index (e.g., '0')"
 echo "   • First to 50 points wins!"
 echo ""
@@ -55,6 +56,7 @@ echo "Press Ctrl+C to stop the server"
 echo ""
 
 # Wait for Ctrl+C
-trap "kill $SERVER_PID 2>/dev/null; pkill -f chicago-poker 2>/dev/null; echo ''; echo '✓ Server stopped'; rm -f server.log; exit 0" INT TERM
+# The server saves running games and says goodbye to its players on SIGTERM
+trap "echo ''; echo 'Stopping the server...'; pkill -f chicago-poker 2>/dev/null; wait; echo '✓ Server stopped'; rm -f server.log; exit 0" INT TERM
 
 wait
@@ -28,12 +28,16 @@ if ! ps -p $SERVER_PID > /dev/null; then
 fi
 echo "✓ Server started"
 
-# Connect player 1
+# Connect player 1, who opens a table
 echo "Connecting Player1..."
 {
     sleep 1
     echo "Player1"
+    sleep 1
+    echo "create t"
     sleep 2
+    echo "ready"
+    sleep 3
     echo ""
     sleep 2
     echo "0"
@@ -42,19 +46,23 @@ echo "Connecting Player1..."
 P1_PID=$!
 sleep 1
 
-# Connect player 2
+# Connect player 2, who joins it; the game starts once both are ready
 echo "Connecting Player2..."
 {
     sleep 1
     echo "Player2"
     sleep 2
+    echo "join t"
+    sleep 1
+    echo "ready"
+    sleep 3
     echo ""
     sleep 2
     echo "0"
     sleep 20
 } | nc localhost 8080 > player2.log 2>&1 &
 P2_PID=$!
-sleep 5
+sleep 8
 
 # Check outputs
 echo ""